@@ -0,0 +1,140 @@
+// Package backend defines the interface that a container runtime must
+// implement in order to be driven by the Warden server.
+package backend
+
+import (
+	"io"
+	"time"
+)
+
+// Backend is implemented by something capable of creating and managing
+// containers. The server talks to a Backend; it never touches containers
+// directly.
+type Backend interface {
+	Start() error
+	Stop()
+
+	Create(spec ContainerSpec) (Container, error)
+	Destroy(handle string, auth AuthContext) error
+
+	Containers() ([]Container, error)
+	Lookup(handle string) (Container, error)
+}
+
+// ContainerSpec describes the container to create.
+type ContainerSpec struct {
+	Handle string
+
+	GraceTime time.Duration
+
+	Network string
+
+	RootFSPath string
+
+	BindMounts []BindMount
+
+	// Auth identifies the client that asked for this container, as
+	// established by the transport (e.g. a verified TLS client cert's
+	// common name). Backends that enforce per-client authorization can
+	// consult it; backends that don't can ignore it.
+	Auth AuthContext
+}
+
+// AuthContext identifies the client on whose behalf a request is being
+// made, as established by the transport the request arrived on.
+type AuthContext struct {
+	// CommonName is the CN of the client's verified TLS certificate, or
+	// empty if the transport didn't authenticate the client.
+	CommonName string
+}
+
+// BindMount describes a single bind mount to set up inside the container.
+type BindMount struct {
+	SrcPath string
+	DstPath string
+	Mode    BindMountMode
+}
+
+// BindMountMode controls whether a bind mount is read-only or read-write.
+type BindMountMode uint32
+
+const (
+	BindMountModeRO BindMountMode = iota
+	BindMountModeRW
+)
+
+// JobSpec describes a process to spawn inside a container.
+type JobSpec struct {
+	Script               string
+	EnvironmentVariables []EnvironmentVariable
+}
+
+// EnvironmentVariable is a single K=V pair passed to a spawned job.
+type EnvironmentVariable struct {
+	Key   string
+	Value string
+}
+
+// JobResult is the outcome of a job that ran to completion.
+type JobResult struct {
+	ExitStatus uint32
+	Stdout     []byte
+	Stderr     []byte
+}
+
+// JobStream is a single chunk of output from a running job.
+type JobStream struct {
+	Name       string // "stdout" or "stderr"; may be empty on the chunk carrying ExitStatus
+	Data       []byte
+	ExitStatus *uint32
+}
+
+// AttachOptions controls how a job is attached to.
+type AttachOptions struct{}
+
+// AttachStreams exposes a running job's stdio as plain io.Reader/io.Writer
+// pairs, so callers can compose them with io.Copy, TLS, gzip, or gRPC
+// streams without buffering entire outputs in memory.
+type AttachStreams struct {
+	Stdin  io.WriteCloser
+	Stdout io.Reader
+	Stderr io.Reader
+
+	Wait func() (exitStatus int, err error)
+}
+
+// Container is a single created container and the operations that can be
+// performed against it.
+type Container interface {
+	Handle() string
+
+	Stop(kill bool) error
+
+	Spawn(spec JobSpec) (uint32, error)
+	Stream(jobID uint32) (<-chan JobStream, error)
+	Run(spec JobSpec) (JobResult, error)
+
+	// Attach connects to a running job's stdio. Unlike Stream, which only
+	// yields output, the returned AttachStreams.Stdin lets the caller push
+	// bytes to the job while it's running.
+	Attach(jobID uint32, opts AttachOptions) (AttachStreams, error)
+
+	// StreamIn tars the bytes read from r into the container at dstPath.
+	StreamIn(dstPath string, r io.Reader) error
+
+	// StreamOut tars srcPath out of the container. The caller must Close
+	// the returned ReadCloser once done reading.
+	StreamOut(srcPath string) (io.ReadCloser, error)
+
+	NetIn(hostPort, containerPort uint32) (uint32, uint32, error)
+	NetOut(network string, port uint32) error
+
+	LimitMemory(limit uint64) error
+	CurrentMemoryLimit() (uint64, error)
+
+	LimitDisk(limit uint64) error
+	CurrentDiskLimit() (uint64, error)
+
+	LimitCPU(shares uint64) error
+	CurrentCPULimit() (uint64, error)
+}
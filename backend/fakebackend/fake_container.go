@@ -0,0 +1,137 @@
+package fakebackend
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/vito/garden/backend"
+)
+
+// FakeContainer is the backend.Container handed back by FakeBackend.Create.
+// It keeps the spec it was created with around so tests can assert on it.
+type FakeContainer struct {
+	Spec backend.ContainerSpec
+
+	MemoryLimit uint64
+	DiskLimit   uint64
+	CPULimit    uint64
+
+	streamedFiles   map[string][]byte
+	streamedFilesMu sync.Mutex
+}
+
+// NewFakeContainer returns a FakeContainer for the given spec.
+func NewFakeContainer(spec backend.ContainerSpec) *FakeContainer {
+	return &FakeContainer{Spec: spec}
+}
+
+func (c *FakeContainer) Handle() string { return c.Spec.Handle }
+
+func (c *FakeContainer) Stop(kill bool) error { return nil }
+
+func (c *FakeContainer) Spawn(spec backend.JobSpec) (uint32, error) { return 0, nil }
+
+func (c *FakeContainer) Stream(jobID uint32) (<-chan backend.JobStream, error) {
+	stream := make(chan backend.JobStream)
+	close(stream)
+	return stream, nil
+}
+
+func (c *FakeContainer) Run(spec backend.JobSpec) (backend.JobResult, error) {
+	return backend.JobResult{}, nil
+}
+
+// Attach returns an echo loop: anything written to Stdin comes back out
+// of Stdout. It exists so callers of the real backend.Container.Attach
+// contract can be tested without a real running job.
+func (c *FakeContainer) Attach(jobID uint32, opts backend.AttachOptions) (backend.AttachStreams, error) {
+	stdinR, stdinW := io.Pipe()
+	stdoutR, stdoutW := io.Pipe()
+
+	done := make(chan struct{})
+
+	go func() {
+		io.Copy(stdoutW, stdinR)
+		stdoutW.Close()
+		close(done)
+	}()
+
+	return backend.AttachStreams{
+		Stdin:  stdinW,
+		Stdout: stdoutR,
+		Stderr: new(bytesEOFReader),
+		Wait: func() (int, error) {
+			<-done
+			return 0, nil
+		},
+	}, nil
+}
+
+// bytesEOFReader is an io.Reader that immediately reports EOF; it stands
+// in for a job's stderr in Attach's echo loop, which never writes to it.
+type bytesEOFReader struct{}
+
+func (*bytesEOFReader) Read(p []byte) (int, error) { return 0, io.EOF }
+
+// StreamIn records the bytes read from r under dstPath so a later
+// StreamOut of the same path can hand them back.
+func (c *FakeContainer) StreamIn(dstPath string, r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	c.streamedFilesMu.Lock()
+	defer c.streamedFilesMu.Unlock()
+
+	if c.streamedFiles == nil {
+		c.streamedFiles = make(map[string][]byte)
+	}
+
+	c.streamedFiles[dstPath] = data
+
+	return nil
+}
+
+// StreamOut hands back the bytes most recently StreamIn'd to srcPath.
+func (c *FakeContainer) StreamOut(srcPath string) (io.ReadCloser, error) {
+	c.streamedFilesMu.Lock()
+	defer c.streamedFilesMu.Unlock()
+
+	data, found := c.streamedFiles[srcPath]
+	if !found {
+		return nil, errors.New("no such file: " + srcPath)
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (c *FakeContainer) NetIn(hostPort, containerPort uint32) (uint32, uint32, error) {
+	return hostPort, containerPort, nil
+}
+
+func (c *FakeContainer) NetOut(network string, port uint32) error { return nil }
+
+func (c *FakeContainer) LimitMemory(limit uint64) error {
+	c.MemoryLimit = limit
+	return nil
+}
+
+func (c *FakeContainer) CurrentMemoryLimit() (uint64, error) { return c.MemoryLimit, nil }
+
+func (c *FakeContainer) LimitDisk(limit uint64) error {
+	c.DiskLimit = limit
+	return nil
+}
+
+func (c *FakeContainer) CurrentDiskLimit() (uint64, error) { return c.DiskLimit, nil }
+
+func (c *FakeContainer) LimitCPU(shares uint64) error {
+	c.CPULimit = shares
+	return nil
+}
+
+func (c *FakeContainer) CurrentCPULimit() (uint64, error) { return c.CPULimit, nil }
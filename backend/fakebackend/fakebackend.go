@@ -0,0 +1,82 @@
+// Package fakebackend provides an in-memory backend.Backend implementation
+// for exercising the server without a real container runtime.
+package fakebackend
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/vito/garden/backend"
+)
+
+// FakeBackend records the containers it's asked to create so tests can
+// assert on them afterward.
+type FakeBackend struct {
+	CreatedContainers map[string]*FakeContainer
+
+	ContainerCreationError error
+
+	sync.RWMutex
+}
+
+// New returns a ready-to-use FakeBackend.
+func New() *FakeBackend {
+	return &FakeBackend{
+		CreatedContainers: make(map[string]*FakeContainer),
+	}
+}
+
+func (b *FakeBackend) Start() error { return nil }
+func (b *FakeBackend) Stop()        {}
+
+func (b *FakeBackend) Create(spec backend.ContainerSpec) (backend.Container, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.ContainerCreationError != nil {
+		return nil, b.ContainerCreationError
+	}
+
+	container := NewFakeContainer(spec)
+
+	b.CreatedContainers[spec.Handle] = container
+
+	return container, nil
+}
+
+func (b *FakeBackend) Destroy(handle string, auth backend.AuthContext) error {
+	b.Lock()
+	defer b.Unlock()
+
+	if _, found := b.CreatedContainers[handle]; !found {
+		return errors.New("unknown handle: " + handle)
+	}
+
+	delete(b.CreatedContainers, handle)
+
+	return nil
+}
+
+func (b *FakeBackend) Containers() ([]backend.Container, error) {
+	b.RLock()
+	defer b.RUnlock()
+
+	containers := make([]backend.Container, 0, len(b.CreatedContainers))
+	for _, c := range b.CreatedContainers {
+		containers = append(containers, c)
+	}
+
+	return containers, nil
+}
+
+func (b *FakeBackend) Lookup(handle string) (backend.Container, error) {
+	b.RLock()
+	defer b.RUnlock()
+
+	container, found := b.CreatedContainers[handle]
+	if !found {
+		return nil, errors.New("unknown handle: " + handle)
+	}
+
+	return container, nil
+}
@@ -0,0 +1,128 @@
+//go:build linux
+
+package linuxbackend
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/vito/garden/backend"
+)
+
+// LinuxBackend creates containers as Linux namespaces rooted at an
+// overlay filesystem, bridged onto the host network via veth pairs.
+type LinuxBackend struct {
+	// DepotPath is where each container's rootfs overlay lives, one
+	// subdirectory per handle. Cgroups live under /sys/fs/cgroup/garden
+	// instead.
+	DepotPath string
+
+	// BridgeName is the host bridge interface that container veth pairs
+	// are attached to.
+	BridgeName string
+
+	mu         sync.Mutex
+	containers map[string]*LinuxContainer
+}
+
+// New returns a LinuxBackend rooted at depotPath, bridging containers
+// onto bridgeName.
+func New(depotPath string, bridgeName string) *LinuxBackend {
+	return &LinuxBackend{
+		DepotPath:  depotPath,
+		BridgeName: bridgeName,
+		containers: make(map[string]*LinuxContainer),
+	}
+}
+
+// Start ensures the depot directory exists.
+func (b *LinuxBackend) Start() error {
+	return os.MkdirAll(b.DepotPath, 0755)
+}
+
+// Stop destroys every container the backend knows about.
+func (b *LinuxBackend) Stop() {
+	b.mu.Lock()
+	containers := make([]*LinuxContainer, 0, len(b.containers))
+	for _, container := range b.containers {
+		containers = append(containers, container)
+	}
+	b.mu.Unlock()
+
+	for _, container := range containers {
+		container.destroy()
+	}
+}
+
+// Create sets up a network namespace, bind mounts, and an overlay rootfs
+// for spec, and returns the resulting Container.
+func (b *LinuxBackend) Create(spec backend.ContainerSpec) (backend.Container, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if spec.Handle == "" {
+		return nil, errors.New("linuxbackend: handle is required")
+	}
+
+	if _, found := b.containers[spec.Handle]; found {
+		return nil, fmt.Errorf("linuxbackend: handle already in use: %s", spec.Handle)
+	}
+
+	depotPath, err := containedPath(b.DepotPath, spec.Handle)
+	if err != nil {
+		return nil, fmt.Errorf("linuxbackend: %w", err)
+	}
+
+	container, err := createContainer(depotPath, b.BridgeName, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	b.containers[spec.Handle] = container
+
+	return container, nil
+}
+
+// Destroy tears down the container's namespace, mounts, and cgroup.
+func (b *LinuxBackend) Destroy(handle string, auth backend.AuthContext) error {
+	b.mu.Lock()
+	container, found := b.containers[handle]
+	if found {
+		delete(b.containers, handle)
+	}
+	b.mu.Unlock()
+
+	if !found {
+		return fmt.Errorf("linuxbackend: unknown handle: %s", handle)
+	}
+
+	return container.destroy()
+}
+
+// Containers returns every container currently tracked by the backend.
+func (b *LinuxBackend) Containers() ([]backend.Container, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	containers := make([]backend.Container, 0, len(b.containers))
+	for _, container := range b.containers {
+		containers = append(containers, container)
+	}
+
+	return containers, nil
+}
+
+// Lookup returns the container with the given handle.
+func (b *LinuxBackend) Lookup(handle string) (backend.Container, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	container, found := b.containers[handle]
+	if !found {
+		return nil, fmt.Errorf("linuxbackend: unknown handle: %s", handle)
+	}
+
+	return container, nil
+}
@@ -0,0 +1,56 @@
+//go:build linux
+
+package linuxbackend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const cgroupRoot = "/sys/fs/cgroup/garden"
+
+// createCgroup creates a cgroup v2 leaf for handle and returns its path.
+// handle is validated against cgroupRoot the same way tar entries and
+// stream paths are, since it ultimately comes from the client's
+// ContainerSpec.
+func createCgroup(handle string) (string, error) {
+	path, err := containedPath(cgroupRoot, handle)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func removeCgroup(path string) {
+	os.Remove(path)
+}
+
+func writeCgroupFile(cgroupPath, file, value string) error {
+	return os.WriteFile(filepath.Join(cgroupPath, file), []byte(value), 0644)
+}
+
+func readCgroupUint(cgroupPath, file string) (uint64, error) {
+	data, err := os.ReadFile(filepath.Join(cgroupPath, file))
+	if err != nil {
+		return 0, err
+	}
+
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s: %w", file, err)
+	}
+
+	return parsed, nil
+}
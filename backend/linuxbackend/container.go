@@ -0,0 +1,232 @@
+//go:build linux
+
+package linuxbackend
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/vito/garden/backend"
+)
+
+// LinuxContainer is a container rooted at an overlay filesystem, inside
+// its own network namespace, bridged onto the host via a veth pair.
+type LinuxContainer struct {
+	handle string
+	spec   backend.ContainerSpec
+
+	// depotPath holds this container's rootfs overlay (the mount point and
+	// its upper and work dirs). The namespaces are held open by the init
+	// process (initPID), and the cgroup lives under cgroupPath.
+	depotPath  string
+	bridgeName string
+	cgroupPath string
+
+	// initPID is the PID of the long-lived process holding the
+	// container's namespaces open; Spawn/Run join it with nsenter.
+	initCmd *exec.Cmd
+	initPID int
+
+	mu        sync.Mutex
+	jobs      map[uint32]*job
+	nextJobID uint32
+
+	// graceTimer destroys the container after spec.GraceTime of
+	// inactivity; touch resets it.
+	graceTimer *time.Timer
+}
+
+// createContainer sets up depotPath's overlay rootfs, a veth pair
+// bridged onto bridgeName, and a cgroup, then starts the init process
+// that holds the resulting namespaces open for Spawn/Run to join.
+func createContainer(depotPath, bridgeName string, spec backend.ContainerSpec) (*LinuxContainer, error) {
+	if err := os.MkdirAll(depotPath, 0755); err != nil {
+		return nil, err
+	}
+
+	rootfsPath := filepath.Join(depotPath, "rootfs")
+	if err := setUpOverlay(rootfsPath, spec.RootFSPath, spec.BindMounts); err != nil {
+		return nil, fmt.Errorf("linuxbackend: overlay setup: %w", err)
+	}
+
+	cgroupPath, err := createCgroup(spec.Handle)
+	if err != nil {
+		return nil, fmt.Errorf("linuxbackend: cgroup setup: %w", err)
+	}
+
+	initCmd, err := startInit(rootfsPath, cgroupPath)
+	if err != nil {
+		return nil, fmt.Errorf("linuxbackend: init process: %w", err)
+	}
+
+	if err := setUpVeth(initCmd.Process.Pid, bridgeName, spec.Handle); err != nil {
+		return nil, fmt.Errorf("linuxbackend: network setup: %w", err)
+	}
+
+	container := &LinuxContainer{
+		handle:     spec.Handle,
+		spec:       spec,
+		depotPath:  depotPath,
+		bridgeName: bridgeName,
+		cgroupPath: cgroupPath,
+		initCmd:    initCmd,
+		initPID:    initCmd.Process.Pid,
+		jobs:       make(map[uint32]*job),
+	}
+
+	if spec.GraceTime > 0 {
+		container.graceTimer = time.AfterFunc(spec.GraceTime, func() {
+			container.destroy()
+		})
+	}
+
+	return container, nil
+}
+
+func (c *LinuxContainer) Handle() string {
+	return c.handle
+}
+
+// Stop kills every job running in the container. kill chooses SIGKILL
+// over SIGTERM.
+func (c *LinuxContainer) Stop(kill bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, j := range c.jobs {
+		j.signal(kill)
+	}
+
+	return nil
+}
+
+// touch resets the container's idle-destroy timer. Spawn, Stream, Run,
+// Attach, StreamIn and StreamOut call it; the other Container methods do
+// not, and a job that is still running does not reset the timer by itself.
+func (c *LinuxContainer) touch() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.graceTimer != nil {
+		c.graceTimer.Reset(c.spec.GraceTime)
+	}
+}
+
+func (c *LinuxContainer) destroy() error {
+	c.mu.Lock()
+	if c.graceTimer != nil {
+		c.graceTimer.Stop()
+	}
+	c.mu.Unlock()
+
+	c.Stop(true)
+
+	if c.initCmd.Process != nil {
+		c.initCmd.Process.Kill()
+		c.initCmd.Wait()
+	}
+
+	tearDownVeth(c.handle)
+	removeCgroup(c.cgroupPath)
+	tearDownOverlay(filepath.Join(c.depotPath, "rootfs"))
+
+	return os.RemoveAll(c.depotPath)
+}
+
+func (c *LinuxContainer) allocateJobID() uint32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextJobID++
+	return c.nextJobID
+}
+
+func (c *LinuxContainer) trackJob(id uint32, j *job) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.jobs[id] = j
+}
+
+func (c *LinuxContainer) getJob(id uint32) (*job, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	j, found := c.jobs[id]
+	return j, found
+}
+
+func (c *LinuxContainer) LimitMemory(limit uint64) error {
+	return writeCgroupFile(c.cgroupPath, "memory.max", fmt.Sprintf("%d", limit))
+}
+
+func (c *LinuxContainer) CurrentMemoryLimit() (uint64, error) {
+	return readCgroupUint(c.cgroupPath, "memory.max")
+}
+
+func (c *LinuxContainer) LimitDisk(limit uint64) error {
+	return setProjectQuota(filepath.Join(c.depotPath, "rootfs"), limit)
+}
+
+func (c *LinuxContainer) CurrentDiskLimit() (uint64, error) {
+	return getProjectQuota(filepath.Join(c.depotPath, "rootfs"))
+}
+
+func (c *LinuxContainer) LimitCPU(shares uint64) error {
+	return writeCgroupFile(c.cgroupPath, "cpu.weight", fmt.Sprintf("%d", shares))
+}
+
+func (c *LinuxContainer) CurrentCPULimit() (uint64, error) {
+	return readCgroupUint(c.cgroupPath, "cpu.weight")
+}
+
+func (c *LinuxContainer) NetIn(hostPort, containerPort uint32) (uint32, uint32, error) {
+	if hostPort == 0 {
+		allocated, err := allocatePort()
+		if err != nil {
+			return 0, 0, err
+		}
+		hostPort = allocated
+	}
+
+	if err := addPortForward(c.handle, hostPort, containerPort); err != nil {
+		return 0, 0, err
+	}
+
+	return hostPort, containerPort, nil
+}
+
+func (c *LinuxContainer) NetOut(network string, port uint32) error {
+	return addEgressRule(c.handle, network, port)
+}
+
+// StreamIn tars the bytes read from r into the container's rootfs at
+// dstPath, which must resolve inside the rootfs (see containedPath) -
+// DstPath comes straight off the wire, so a "../" here would otherwise
+// write to the host.
+func (c *LinuxContainer) StreamIn(dstPath string, r io.Reader) error {
+	c.touch()
+
+	target, err := containedPath(filepath.Join(c.depotPath, "rootfs"), dstPath)
+	if err != nil {
+		return err
+	}
+
+	return extractTar(r, target)
+}
+
+// StreamOut tars srcPath out of the container's rootfs, which must
+// resolve inside the rootfs; see StreamIn.
+func (c *LinuxContainer) StreamOut(srcPath string) (io.ReadCloser, error) {
+	c.touch()
+
+	target, err := containedPath(filepath.Join(c.depotPath, "rootfs"), srcPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return createTar(target)
+}
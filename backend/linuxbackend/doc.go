@@ -0,0 +1,12 @@
+//go:build linux
+
+// Package linuxbackend implements backend.Backend on top of Linux network
+// namespaces, cgroup v2, and an overlay rootfs. It's the backend that
+// actually runs something, as opposed to fakebackend, which just records
+// what it was asked to do.
+//
+// It requires CAP_SYS_ADMIN (to create namespaces and mounts) and a
+// cgroup v2 hierarchy mounted at /sys/fs/cgroup, so it only builds under
+// //go:build linux and is exercised by its own integration suite rather
+// than the server tests, which run against fakebackend.
+package linuxbackend
@@ -0,0 +1,35 @@
+//go:build linux
+
+package linuxbackend
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// startInit forks the long-lived process that holds the container's
+// mount, UTS, IPC, network, and PID namespaces open for the lifetime of
+// the container; Spawn/Run join those namespaces with nsenter.
+func startInit(rootfsPath, cgroupPath string) (*exec.Cmd, error) {
+	cmd := exec.Command("sleep", "infinity")
+
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWNS |
+			syscall.CLONE_NEWUTS |
+			syscall.CLONE_NEWIPC |
+			syscall.CLONE_NEWNET |
+			syscall.CLONE_NEWPID,
+		Chroot: rootfsPath,
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	if err := writeCgroupFile(cgroupPath, "cgroup.procs", itoa(cmd.Process.Pid)); err != nil {
+		cmd.Process.Kill()
+		return nil, err
+	}
+
+	return cmd, nil
+}
@@ -0,0 +1,305 @@
+//go:build linux
+
+package linuxbackend
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"syscall"
+
+	"github.com/vito/garden/backend"
+)
+
+// job is a process running inside the container's namespaces, joined via
+// nsenter against the container's init process.
+type job struct {
+	cmd *exec.Cmd
+
+	stdin  io.WriteCloser
+	stdout *streamBuffer
+	stderr *streamBuffer
+
+	mu         sync.Mutex
+	exitStatus *int
+	exitErr    error
+	waiters    []chan struct{}
+}
+
+// nsenterCommand builds the command used to join a running job (or the
+// supervisor itself) to the namespaces held open by the init process at
+// initPID. Joining the mount namespace with --mount alone does not change
+// the spawned process's root directory, so it would otherwise run against
+// the host's filesystem; --root/--wd point nsenter at the init process's
+// own root (chrooted to the overlay rootfs by startInit) and chroot/chdir
+// the job there after the namespaces are joined.
+func nsenterCommand(initPID int, script string) *exec.Cmd {
+	root := fmt.Sprintf("/proc/%d/root", initPID)
+	return exec.Command("nsenter",
+		"--target", itoa(initPID),
+		"--mount", "--uts", "--ipc", "--net", "--pid",
+		"--root="+root, "--wd=/",
+		"--", "/bin/sh", "-c", script,
+	)
+}
+
+func (c *LinuxContainer) spawn(spec backend.JobSpec) (*job, uint32, error) {
+	cmd := nsenterCommand(c.initPID, spec.Script)
+
+	for _, env := range spec.EnvironmentVariables {
+		cmd.Env = append(cmd.Env, env.Key+"="+env.Value)
+	}
+
+	stdinR, stdinW := io.Pipe()
+
+	j := &job{
+		stdin:  stdinW,
+		stdout: newStreamBuffer(),
+		stderr: newStreamBuffer(),
+	}
+
+	cmd.Stdin = stdinR
+	cmd.Stdout = j.stdout
+	cmd.Stderr = j.stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, 0, err
+	}
+
+	// nsenter joins the init process's namespaces but not its cgroup, so
+	// the spawned job has to be moved into the container's cgroup itself
+	// or LimitMemory/LimitCPU would only ever constrain the init process.
+	if err := writeCgroupFile(c.cgroupPath, "cgroup.procs", itoa(cmd.Process.Pid)); err != nil {
+		cmd.Process.Kill()
+		return nil, 0, err
+	}
+
+	j.cmd = cmd
+
+	jobID := c.allocateJobID()
+	c.trackJob(jobID, j)
+
+	go j.wait()
+
+	return j, jobID, nil
+}
+
+func (j *job) wait() {
+	err := j.cmd.Wait()
+
+	status := 0
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		status = exitErr.ExitCode()
+		err = nil
+	}
+
+	j.mu.Lock()
+	j.exitStatus = &status
+	j.exitErr = err
+	waiters := j.waiters
+	j.waiters = nil
+	j.mu.Unlock()
+
+	j.stdout.closeForWriting()
+	j.stderr.closeForWriting()
+
+	for _, w := range waiters {
+		close(w)
+	}
+}
+
+func (j *job) signal(kill bool) {
+	if j.cmd.Process == nil {
+		return
+	}
+
+	sig := syscall.SIGTERM
+	if kill {
+		sig = syscall.SIGKILL
+	}
+
+	j.cmd.Process.Signal(sig)
+}
+
+// waitForExit blocks until the job has exited and returns its status.
+func (j *job) waitForExit() (int, error) {
+	j.mu.Lock()
+	if j.exitStatus != nil {
+		status, err := *j.exitStatus, j.exitErr
+		j.mu.Unlock()
+		return status, err
+	}
+
+	done := make(chan struct{})
+	j.waiters = append(j.waiters, done)
+	j.mu.Unlock()
+
+	<-done
+
+	return *j.exitStatus, j.exitErr
+}
+
+func (c *LinuxContainer) Spawn(spec backend.JobSpec) (uint32, error) {
+	c.touch()
+
+	_, jobID, err := c.spawn(spec)
+	return jobID, err
+}
+
+func (c *LinuxContainer) Stream(jobID uint32) (<-chan backend.JobStream, error) {
+	c.touch()
+
+	j, found := c.getJob(jobID)
+	if !found {
+		return nil, errUnknownJob(jobID)
+	}
+
+	out := make(chan backend.JobStream)
+
+	go func() {
+		defer close(out)
+
+		stdout := j.stdout.reader()
+		stderr := j.stderr.reader()
+
+		pump := func(name string, r io.Reader, done chan<- struct{}) {
+			buf := make([]byte, 4096)
+			for {
+				n, err := r.Read(buf)
+				if n > 0 {
+					data := make([]byte, n)
+					copy(data, buf[:n])
+					out <- backend.JobStream{Name: name, Data: data}
+				}
+				if err != nil {
+					close(done)
+					return
+				}
+			}
+		}
+
+		stdoutDone := make(chan struct{})
+		stderrDone := make(chan struct{})
+
+		go pump("stdout", stdout, stdoutDone)
+		go pump("stderr", stderr, stderrDone)
+
+		<-stdoutDone
+		<-stderrDone
+
+		status, _ := j.waitForExit()
+		exitStatus := uint32(status)
+		out <- backend.JobStream{ExitStatus: &exitStatus}
+	}()
+
+	return out, nil
+}
+
+func (c *LinuxContainer) Run(spec backend.JobSpec) (backend.JobResult, error) {
+	c.touch()
+
+	j, _, err := c.spawn(spec)
+	if err != nil {
+		return backend.JobResult{}, err
+	}
+
+	status, err := j.waitForExit()
+	if err != nil {
+		return backend.JobResult{}, err
+	}
+
+	return backend.JobResult{
+		ExitStatus: uint32(status),
+		Stdout:     j.stdout.collected(),
+		Stderr:     j.stderr.collected(),
+	}, nil
+}
+
+// Attach exposes a running job's stdio as a Reader/Writer pair, backed by
+// the same pipe spawn wired up to the nsenter'd process's stdin, so writes
+// here reach the job whether or not anyone has attached before.
+func (c *LinuxContainer) Attach(jobID uint32, opts backend.AttachOptions) (backend.AttachStreams, error) {
+	c.touch()
+
+	j, found := c.getJob(jobID)
+	if !found {
+		return backend.AttachStreams{}, errUnknownJob(jobID)
+	}
+
+	return backend.AttachStreams{
+		Stdin:  j.stdin,
+		Stdout: j.stdout.reader(),
+		Stderr: j.stderr.reader(),
+		Wait: func() (int, error) {
+			return j.waitForExit()
+		},
+	}, nil
+}
+
+// streamBuffer is an io.Writer that multiple readers can Read from
+// concurrently with the writer, used to fan a job's stdout/stderr out to
+// both Stream/Attach (live) and Run (collected in full).
+type streamBuffer struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	closed bool
+	cond   *sync.Cond
+}
+
+func newStreamBuffer() *streamBuffer {
+	s := &streamBuffer{}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+func (s *streamBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, err := s.buf.Write(p)
+	s.cond.Broadcast()
+	return n, err
+}
+
+func (s *streamBuffer) closeForWriting() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.cond.Broadcast()
+}
+
+func (s *streamBuffer) collected() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Bytes()
+}
+
+// reader returns an io.Reader over everything written so far and
+// anything written in the future, up until the buffer is closed.
+func (s *streamBuffer) reader() io.Reader {
+	return &streamBufferReader{buf: s}
+}
+
+type streamBufferReader struct {
+	buf    *streamBuffer
+	offset int
+}
+
+func (r *streamBufferReader) Read(p []byte) (int, error) {
+	r.buf.mu.Lock()
+	defer r.buf.mu.Unlock()
+
+	for r.offset >= r.buf.buf.Len() && !r.buf.closed {
+		r.buf.cond.Wait()
+	}
+
+	if r.offset >= r.buf.buf.Len() && r.buf.closed {
+		return 0, io.EOF
+	}
+
+	n := copy(p, r.buf.buf.Bytes()[r.offset:])
+	r.offset += n
+
+	return n, nil
+}
@@ -0,0 +1,15 @@
+//go:build linux
+
+package linuxbackend_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestLinuxbackend(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Linuxbackend Suite")
+}
@@ -0,0 +1,82 @@
+//go:build linux
+
+package linuxbackend_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vito/garden/backend"
+	"github.com/vito/garden/backend/linuxbackend"
+)
+
+// These tests exercise real namespaces, cgroups, and mounts, so they
+// need CAP_SYS_ADMIN; run them inside a privileged container, e.g.
+//
+//	docker run --privileged -v $PWD:/garden -w /garden golang:1 \
+//	  go test ./backend/linuxbackend/...
+var _ = Describe("LinuxBackend", func() {
+	var depotPath string
+	var linuxBackend *linuxbackend.LinuxBackend
+
+	BeforeEach(func() {
+		if os.Getuid() != 0 {
+			Skip("requires root/CAP_SYS_ADMIN; run inside a privileged container")
+		}
+
+		var err error
+		depotPath, err = ioutil.TempDir(os.TempDir(), "linuxbackend-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		linuxBackend = linuxbackend.New(depotPath, "wardenbr0")
+
+		err = linuxBackend.Start()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		if linuxBackend != nil {
+			linuxBackend.Stop()
+		}
+	})
+
+	It("creates a container with the requested handle", func() {
+		container, err := linuxBackend.Create(backend.ContainerSpec{
+			Handle:     "some-handle",
+			RootFSPath: "/var/lib/garden/rootfs-base",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(container.Handle()).To(Equal("some-handle"))
+	})
+
+	It("runs a job to completion and captures its output", func() {
+		container, err := linuxBackend.Create(backend.ContainerSpec{
+			Handle:     "some-handle",
+			RootFSPath: "/var/lib/garden/rootfs-base",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := container.Run(backend.JobSpec{Script: "echo hi"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.ExitStatus).To(Equal(uint32(0)))
+		Expect(string(result.Stdout)).To(Equal("hi\n"))
+	})
+
+	It("enforces a memory limit via cgroup v2", func() {
+		container, err := linuxBackend.Create(backend.ContainerSpec{
+			Handle:     "some-handle",
+			RootFSPath: "/var/lib/garden/rootfs-base",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		err = container.LimitMemory(64 * 1024 * 1024)
+		Expect(err).ToNot(HaveOccurred())
+
+		limit, err := container.CurrentMemoryLimit()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(limit).To(Equal(uint64(64 * 1024 * 1024)))
+	})
+})
@@ -0,0 +1,69 @@
+//go:build linux
+
+package linuxbackend
+
+import (
+	"fmt"
+	"net"
+)
+
+// setUpVeth creates a veth pair, puts one end into the namespace held by
+// initPID, and attaches the other end to bridgeName.
+func setUpVeth(initPID int, bridgeName, handle string) error {
+	hostSide := vethName(handle, "host")
+	containerSide := vethName(handle, "ctr")
+
+	if err := run("ip", "link", "add", hostSide, "type", "veth", "peer", "name", containerSide); err != nil {
+		return err
+	}
+
+	if err := run("ip", "link", "set", containerSide, "netns", itoa(initPID)); err != nil {
+		return err
+	}
+
+	if err := run("ip", "link", "set", hostSide, "master", bridgeName); err != nil {
+		return err
+	}
+
+	return run("ip", "link", "set", hostSide, "up")
+}
+
+func tearDownVeth(handle string) {
+	run("ip", "link", "del", vethName(handle, "host"))
+}
+
+func vethName(handle, side string) string {
+	if len(handle) > 8 {
+		handle = handle[:8]
+	}
+	return fmt.Sprintf("w-%s-%s", handle, side)
+}
+
+// allocatePort asks the kernel for an unused TCP port by briefly binding
+// to port 0.
+func allocatePort() (uint32, error) {
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+
+	return uint32(listener.Addr().(*net.TCPAddr).Port), nil
+}
+
+func addPortForward(handle string, hostPort, containerPort uint32) error {
+	return run("iptables", "-t", "nat", "-A", "PREROUTING",
+		"-p", "tcp", "--dport", itoa(int(hostPort)),
+		"-j", "DNAT", "--to-destination", fmt.Sprintf(":%d", containerPort),
+		"-m", "comment", "--comment", "garden:"+handle)
+}
+
+func addEgressRule(handle string, network string, port uint32) error {
+	args := []string{"-A", "FORWARD", "-d", network}
+	if port != 0 {
+		args = append(args, "-p", "tcp", "--dport", itoa(int(port)))
+	}
+	args = append(args, "-j", "ACCEPT", "-m", "comment", "--comment", "garden:"+handle)
+
+	return run("iptables", args...)
+}
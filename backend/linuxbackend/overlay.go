@@ -0,0 +1,69 @@
+//go:build linux
+
+package linuxbackend
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/vito/garden/backend"
+)
+
+// setUpOverlay mounts an overlayfs at mountPath with baseImagePath as the
+// (read-only) lower layer, then applies each BindMount on top.
+func setUpOverlay(mountPath, baseImagePath string, bindMounts []backend.BindMount) error {
+	upperDir := mountPath + "-upper"
+	workDir := mountPath + "-work"
+
+	for _, dir := range []string{mountPath, upperDir, workDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	opts := fmt.Sprintf("lowerdir=%s,upperdir=%s,workdir=%s", baseImagePath, upperDir, workDir)
+
+	if err := run("mount", "-t", "overlay", "overlay", "-o", opts, mountPath); err != nil {
+		return fmt.Errorf("mount overlay: %w", err)
+	}
+
+	for _, bindMount := range bindMounts {
+		dst, err := containedPath(mountPath, bindMount.DstPath)
+		if err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(dst, 0755); err != nil {
+			return err
+		}
+
+		mountArgs := []string{"--bind", bindMount.SrcPath, dst}
+		if err := run("mount", mountArgs...); err != nil {
+			return fmt.Errorf("bind mount %s: %w", bindMount.SrcPath, err)
+		}
+
+		if bindMount.Mode == backend.BindMountModeRO {
+			if err := run("mount", "-o", "remount,ro,bind", dst); err != nil {
+				return fmt.Errorf("remount %s read-only: %w", dst, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func tearDownOverlay(mountPath string) {
+	run("umount", "-R", mountPath)
+	os.RemoveAll(mountPath + "-upper")
+	os.RemoveAll(mountPath + "-work")
+}
+
+func run(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %v: %w: %s", name, args, err, out)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+//go:build linux
+
+package linuxbackend
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// extractTar reads a tar stream from r and writes its contents under
+// dstPath, creating directories as needed.
+func extractTar(r io.Reader, dstPath string) error {
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+		return err
+	}
+
+	tarReader := tar.NewReader(r)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := containedPath(dstPath, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.FileMode(header.Mode)); err != nil {
+				return err
+			}
+
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode))
+			if err != nil {
+				return err
+			}
+
+			_, err = io.Copy(file, tarReader)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// containedPath joins dstPath and name, rejecting any name (via "..",
+// a symlink-like absolute path, or otherwise) that would resolve outside
+// of dstPath. This guards extractTar against tar entries crafted to
+// escape the extraction directory ("tar-slip").
+func containedPath(dstPath, name string) (string, error) {
+	target := filepath.Join(dstPath, name)
+
+	dstPath = filepath.Clean(dstPath)
+	if target != dstPath && !strings.HasPrefix(target, dstPath+string(filepath.Separator)) {
+		return "", fmt.Errorf("linuxbackend: tar entry %q escapes destination %q", name, dstPath)
+	}
+
+	return target, nil
+}
+
+// createTar tars up srcPath and returns a ReadCloser streaming the
+// result; the caller must Close it once done reading.
+func createTar(srcPath string) (io.ReadCloser, error) {
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		tarWriter := tar.NewWriter(pipeWriter)
+
+		err := filepath.Walk(srcPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			relPath, err := filepath.Rel(srcPath, path)
+			if err != nil {
+				return err
+			}
+
+			header, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			header.Name = relPath
+
+			if err := tarWriter.WriteHeader(header); err != nil {
+				return err
+			}
+
+			if info.Mode().IsRegular() {
+				file, err := os.Open(path)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+
+				if _, err := io.Copy(tarWriter, file); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+		if err == nil {
+			err = tarWriter.Close()
+		}
+
+		pipeWriter.CloseWithError(err)
+	}()
+
+	return pipeReader, nil
+}
+
+func setProjectQuota(path string, limit uint64) error {
+	return run("xfs_quota", "-x", "-c",
+		"limit -p bsoft="+bytesToBlocks(limit)+" bhard="+bytesToBlocks(limit), path)
+}
+
+// errQuotaUnsupported is returned by getProjectQuota until readback via
+// `xfs_quota -c report` is implemented; it's distinct from a real 0-byte
+// limit so callers can tell "unknown" from "none set".
+var errQuotaUnsupported = fmt.Errorf("linuxbackend: reading back the current disk limit is not implemented")
+
+func getProjectQuota(path string) (uint64, error) {
+	return 0, errQuotaUnsupported
+}
+
+func bytesToBlocks(limit uint64) string {
+	const blockSize = 1024
+	return itoa(int(limit / blockSize))
+}
@@ -0,0 +1,16 @@
+//go:build linux
+
+package linuxbackend
+
+import (
+	"fmt"
+	"strconv"
+)
+
+func itoa(i int) string {
+	return strconv.Itoa(i)
+}
+
+func errUnknownJob(jobID uint32) error {
+	return fmt.Errorf("linuxbackend: unknown job: %d", jobID)
+}
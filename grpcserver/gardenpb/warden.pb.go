@@ -0,0 +1,189 @@
+// Package gardenpb mirrors the message types protoc-gen-go would
+// generate from protocol/warden.proto, hand-written because this tree
+// has no protoc available. It isn't safe to regenerate over - there is
+// no real toolchain output to diff it against.
+package gardenpb
+
+type PingRequest struct{}
+
+func (m *PingRequest) Reset()         { *m = PingRequest{} }
+func (m *PingRequest) String() string { return "PingRequest{}" }
+func (*PingRequest) ProtoMessage()    {}
+
+type PingResponse struct{}
+
+func (m *PingResponse) Reset()         { *m = PingResponse{} }
+func (m *PingResponse) String() string { return "PingResponse{}" }
+func (*PingResponse) ProtoMessage()    {}
+
+type EchoRequest struct {
+	Message string `protobuf:"bytes,1,opt,name=message"`
+}
+
+func (m *EchoRequest) Reset()         { *m = EchoRequest{} }
+func (m *EchoRequest) String() string { return "EchoRequest{}" }
+func (*EchoRequest) ProtoMessage()    {}
+
+type EchoResponse struct {
+	Message string `protobuf:"bytes,1,opt,name=message"`
+}
+
+func (m *EchoResponse) Reset()         { *m = EchoResponse{} }
+func (m *EchoResponse) String() string { return "EchoResponse{}" }
+func (*EchoResponse) ProtoMessage()    {}
+
+type CreateRequest_BindMount_Mode int32
+
+const (
+	CreateRequest_BindMount_RO CreateRequest_BindMount_Mode = 0
+	CreateRequest_BindMount_RW CreateRequest_BindMount_Mode = 1
+)
+
+type CreateRequest_BindMount struct {
+	SrcPath string                       `protobuf:"bytes,1,opt,name=src_path"`
+	DstPath string                       `protobuf:"bytes,2,opt,name=dst_path"`
+	Mode    CreateRequest_BindMount_Mode `protobuf:"varint,3,opt,name=mode"`
+}
+
+func (m *CreateRequest_BindMount) Reset()         { *m = CreateRequest_BindMount{} }
+func (m *CreateRequest_BindMount) String() string { return "CreateRequest_BindMount{}" }
+func (*CreateRequest_BindMount) ProtoMessage()    {}
+
+type CreateRequest struct {
+	Handle     string                     `protobuf:"bytes,1,opt,name=handle"`
+	GraceTime  uint32                     `protobuf:"varint,2,opt,name=grace_time"`
+	Network    string                     `protobuf:"bytes,3,opt,name=network"`
+	Rootfs     string                     `protobuf:"bytes,4,opt,name=rootfs"`
+	BindMounts []*CreateRequest_BindMount `protobuf:"bytes,5,rep,name=bind_mounts"`
+}
+
+func (m *CreateRequest) Reset()         { *m = CreateRequest{} }
+func (m *CreateRequest) String() string { return "CreateRequest{}" }
+func (*CreateRequest) ProtoMessage()    {}
+
+type CreateResponse struct {
+	Handle string `protobuf:"bytes,1,opt,name=handle"`
+}
+
+func (m *CreateResponse) Reset()         { *m = CreateResponse{} }
+func (m *CreateResponse) String() string { return "CreateResponse{}" }
+func (*CreateResponse) ProtoMessage()    {}
+
+type DestroyRequest struct {
+	Handle string `protobuf:"bytes,1,opt,name=handle"`
+}
+
+func (m *DestroyRequest) Reset()         { *m = DestroyRequest{} }
+func (m *DestroyRequest) String() string { return "DestroyRequest{}" }
+func (*DestroyRequest) ProtoMessage()    {}
+
+type DestroyResponse struct{}
+
+func (m *DestroyResponse) Reset()         { *m = DestroyResponse{} }
+func (m *DestroyResponse) String() string { return "DestroyResponse{}" }
+func (*DestroyResponse) ProtoMessage()    {}
+
+type SpawnRequest struct {
+	Handle string `protobuf:"bytes,1,opt,name=handle"`
+	Script string `protobuf:"bytes,2,opt,name=script"`
+}
+
+func (m *SpawnRequest) Reset()         { *m = SpawnRequest{} }
+func (m *SpawnRequest) String() string { return "SpawnRequest{}" }
+func (*SpawnRequest) ProtoMessage()    {}
+
+type SpawnResponse struct {
+	JobId uint32 `protobuf:"varint,1,opt,name=job_id"`
+}
+
+func (m *SpawnResponse) Reset()         { *m = SpawnResponse{} }
+func (m *SpawnResponse) String() string { return "SpawnResponse{}" }
+func (*SpawnResponse) ProtoMessage()    {}
+
+type StreamRequest struct {
+	Handle string `protobuf:"bytes,1,opt,name=handle"`
+	JobId  uint32 `protobuf:"varint,2,opt,name=job_id"`
+}
+
+func (m *StreamRequest) Reset()         { *m = StreamRequest{} }
+func (m *StreamRequest) String() string { return "StreamRequest{}" }
+func (*StreamRequest) ProtoMessage()    {}
+
+type StreamResponse_Source int32
+
+const (
+	StreamResponse_STDOUT StreamResponse_Source = 0
+	StreamResponse_STDERR StreamResponse_Source = 1
+	StreamResponse_EXIT   StreamResponse_Source = 2
+)
+
+type StreamResponse struct {
+	Source     StreamResponse_Source `protobuf:"varint,1,opt,name=source"`
+	Data       []byte                `protobuf:"bytes,2,opt,name=data"`
+	ExitStatus uint32                `protobuf:"varint,3,opt,name=exit_status"`
+}
+
+func (m *StreamResponse) Reset()         { *m = StreamResponse{} }
+func (m *StreamResponse) String() string { return "StreamResponse{}" }
+func (*StreamResponse) ProtoMessage()    {}
+
+type RunRequest struct {
+	Handle string `protobuf:"bytes,1,opt,name=handle"`
+	Script string `protobuf:"bytes,2,opt,name=script"`
+}
+
+func (m *RunRequest) Reset()         { *m = RunRequest{} }
+func (m *RunRequest) String() string { return "RunRequest{}" }
+func (*RunRequest) ProtoMessage()    {}
+
+type NetInRequest struct {
+	Handle        string `protobuf:"bytes,1,opt,name=handle"`
+	HostPort      uint32 `protobuf:"varint,2,opt,name=host_port"`
+	ContainerPort uint32 `protobuf:"varint,3,opt,name=container_port"`
+}
+
+func (m *NetInRequest) Reset()         { *m = NetInRequest{} }
+func (m *NetInRequest) String() string { return "NetInRequest{}" }
+func (*NetInRequest) ProtoMessage()    {}
+
+type NetInResponse struct {
+	HostPort      uint32 `protobuf:"varint,1,opt,name=host_port"`
+	ContainerPort uint32 `protobuf:"varint,2,opt,name=container_port"`
+}
+
+func (m *NetInResponse) Reset()         { *m = NetInResponse{} }
+func (m *NetInResponse) String() string { return "NetInResponse{}" }
+func (*NetInResponse) ProtoMessage()    {}
+
+type NetOutRequest struct {
+	Handle  string `protobuf:"bytes,1,opt,name=handle"`
+	Network string `protobuf:"bytes,2,opt,name=network"`
+	Port    uint32 `protobuf:"varint,3,opt,name=port"`
+}
+
+func (m *NetOutRequest) Reset()         { *m = NetOutRequest{} }
+func (m *NetOutRequest) String() string { return "NetOutRequest{}" }
+func (*NetOutRequest) ProtoMessage()    {}
+
+type NetOutResponse struct{}
+
+func (m *NetOutResponse) Reset()         { *m = NetOutResponse{} }
+func (m *NetOutResponse) String() string { return "NetOutResponse{}" }
+func (*NetOutResponse) ProtoMessage()    {}
+
+type LimitMemoryRequest struct {
+	Handle       string `protobuf:"bytes,1,opt,name=handle"`
+	LimitInBytes uint64 `protobuf:"varint,2,opt,name=limit_in_bytes"`
+}
+
+func (m *LimitMemoryRequest) Reset()         { *m = LimitMemoryRequest{} }
+func (m *LimitMemoryRequest) String() string { return "LimitMemoryRequest{}" }
+func (*LimitMemoryRequest) ProtoMessage()    {}
+
+type LimitMemoryResponse struct {
+	LimitInBytes uint64 `protobuf:"varint,1,opt,name=limit_in_bytes"`
+}
+
+func (m *LimitMemoryResponse) Reset()         { *m = LimitMemoryResponse{} }
+func (m *LimitMemoryResponse) String() string { return "LimitMemoryResponse{}" }
+func (*LimitMemoryResponse) ProtoMessage()    {}
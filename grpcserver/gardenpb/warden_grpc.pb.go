@@ -0,0 +1,390 @@
+// Package gardenpb mirrors the client/server stubs protoc-gen-go-grpc
+// would generate from protocol/warden.proto, hand-written because this
+// tree has no protoc available. It isn't safe to regenerate over - there
+// is no real toolchain output to diff it against.
+package gardenpb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// WardenClient is the client API for the Warden service.
+type WardenClient interface {
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+	Echo(ctx context.Context, in *EchoRequest, opts ...grpc.CallOption) (*EchoResponse, error)
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
+	Destroy(ctx context.Context, in *DestroyRequest, opts ...grpc.CallOption) (*DestroyResponse, error)
+	Spawn(ctx context.Context, in *SpawnRequest, opts ...grpc.CallOption) (*SpawnResponse, error)
+	Stream(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (Warden_StreamClient, error)
+	Run(ctx context.Context, in *RunRequest, opts ...grpc.CallOption) (Warden_RunClient, error)
+	NetIn(ctx context.Context, in *NetInRequest, opts ...grpc.CallOption) (*NetInResponse, error)
+	NetOut(ctx context.Context, in *NetOutRequest, opts ...grpc.CallOption) (*NetOutResponse, error)
+	LimitMemory(ctx context.Context, in *LimitMemoryRequest, opts ...grpc.CallOption) (*LimitMemoryResponse, error)
+}
+
+// Warden_StreamClient is returned by a Stream call.
+type Warden_StreamClient interface {
+	Recv() (*StreamResponse, error)
+	grpc.ClientStream
+}
+
+// Warden_RunClient is returned by a Run call.
+type Warden_RunClient interface {
+	Recv() (*StreamResponse, error)
+	grpc.ClientStream
+}
+
+type wardenClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewWardenClient returns a client for the Warden service on cc.
+func NewWardenClient(cc grpc.ClientConnInterface) WardenClient {
+	return &wardenClient{cc}
+}
+
+func (c *wardenClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error) {
+	out := new(PingResponse)
+	err := c.cc.Invoke(ctx, "/protocol.Warden/Ping", in, out, opts...)
+	return out, err
+}
+
+func (c *wardenClient) Echo(ctx context.Context, in *EchoRequest, opts ...grpc.CallOption) (*EchoResponse, error) {
+	out := new(EchoResponse)
+	err := c.cc.Invoke(ctx, "/protocol.Warden/Echo", in, out, opts...)
+	return out, err
+}
+
+func (c *wardenClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
+	out := new(CreateResponse)
+	err := c.cc.Invoke(ctx, "/protocol.Warden/Create", in, out, opts...)
+	return out, err
+}
+
+func (c *wardenClient) Destroy(ctx context.Context, in *DestroyRequest, opts ...grpc.CallOption) (*DestroyResponse, error) {
+	out := new(DestroyResponse)
+	err := c.cc.Invoke(ctx, "/protocol.Warden/Destroy", in, out, opts...)
+	return out, err
+}
+
+func (c *wardenClient) Spawn(ctx context.Context, in *SpawnRequest, opts ...grpc.CallOption) (*SpawnResponse, error) {
+	out := new(SpawnResponse)
+	err := c.cc.Invoke(ctx, "/protocol.Warden/Spawn", in, out, opts...)
+	return out, err
+}
+
+func (c *wardenClient) Stream(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (Warden_StreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Warden_serviceDesc.Streams[0], "/protocol.Warden/Stream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &wardenStreamClientStream{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type wardenStreamClientStream struct {
+	grpc.ClientStream
+}
+
+func (x *wardenStreamClientStream) Recv() (*StreamResponse, error) {
+	m := new(StreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *wardenClient) Run(ctx context.Context, in *RunRequest, opts ...grpc.CallOption) (Warden_RunClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Warden_serviceDesc.Streams[1], "/protocol.Warden/Run", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &wardenRunClientStream{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type wardenRunClientStream struct {
+	grpc.ClientStream
+}
+
+func (x *wardenRunClientStream) Recv() (*StreamResponse, error) {
+	m := new(StreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *wardenClient) NetIn(ctx context.Context, in *NetInRequest, opts ...grpc.CallOption) (*NetInResponse, error) {
+	out := new(NetInResponse)
+	err := c.cc.Invoke(ctx, "/protocol.Warden/NetIn", in, out, opts...)
+	return out, err
+}
+
+func (c *wardenClient) NetOut(ctx context.Context, in *NetOutRequest, opts ...grpc.CallOption) (*NetOutResponse, error) {
+	out := new(NetOutResponse)
+	err := c.cc.Invoke(ctx, "/protocol.Warden/NetOut", in, out, opts...)
+	return out, err
+}
+
+func (c *wardenClient) LimitMemory(ctx context.Context, in *LimitMemoryRequest, opts ...grpc.CallOption) (*LimitMemoryResponse, error) {
+	out := new(LimitMemoryResponse)
+	err := c.cc.Invoke(ctx, "/protocol.Warden/LimitMemory", in, out, opts...)
+	return out, err
+}
+
+// WardenServer is the server API for the Warden service.
+type WardenServer interface {
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	Echo(context.Context, *EchoRequest) (*EchoResponse, error)
+	Create(context.Context, *CreateRequest) (*CreateResponse, error)
+	Destroy(context.Context, *DestroyRequest) (*DestroyResponse, error)
+	Spawn(context.Context, *SpawnRequest) (*SpawnResponse, error)
+	Stream(*StreamRequest, Warden_StreamServer) error
+	Run(*RunRequest, Warden_RunServer) error
+	NetIn(context.Context, *NetInRequest) (*NetInResponse, error)
+	NetOut(context.Context, *NetOutRequest) (*NetOutResponse, error)
+	LimitMemory(context.Context, *LimitMemoryRequest) (*LimitMemoryResponse, error)
+}
+
+// Warden_StreamServer is the server-side handle for a Stream call.
+type Warden_StreamServer interface {
+	Send(*StreamResponse) error
+	grpc.ServerStream
+}
+
+// Warden_RunServer is the server-side handle for a Run call.
+type Warden_RunServer interface {
+	Send(*StreamResponse) error
+	grpc.ServerStream
+}
+
+// UnimplementedWardenServer may be embedded to have forward compatible
+// implementations.
+type UnimplementedWardenServer struct{}
+
+func (UnimplementedWardenServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
+	return nil, nil
+}
+func (UnimplementedWardenServer) Echo(context.Context, *EchoRequest) (*EchoResponse, error) {
+	return nil, nil
+}
+func (UnimplementedWardenServer) Create(context.Context, *CreateRequest) (*CreateResponse, error) {
+	return nil, nil
+}
+func (UnimplementedWardenServer) Destroy(context.Context, *DestroyRequest) (*DestroyResponse, error) {
+	return nil, nil
+}
+func (UnimplementedWardenServer) Spawn(context.Context, *SpawnRequest) (*SpawnResponse, error) {
+	return nil, nil
+}
+func (UnimplementedWardenServer) Stream(*StreamRequest, Warden_StreamServer) error { return nil }
+func (UnimplementedWardenServer) Run(*RunRequest, Warden_RunServer) error          { return nil }
+func (UnimplementedWardenServer) NetIn(context.Context, *NetInRequest) (*NetInResponse, error) {
+	return nil, nil
+}
+func (UnimplementedWardenServer) NetOut(context.Context, *NetOutRequest) (*NetOutResponse, error) {
+	return nil, nil
+}
+func (UnimplementedWardenServer) LimitMemory(context.Context, *LimitMemoryRequest) (*LimitMemoryResponse, error) {
+	return nil, nil
+}
+
+// RegisterWardenServer registers srv to handle Warden RPCs on s.
+func RegisterWardenServer(s grpc.ServiceRegistrar, srv WardenServer) {
+	s.RegisterService(&_Warden_serviceDesc, srv)
+}
+
+func _Warden_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WardenServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/protocol.Warden/Ping"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WardenServer).Ping(ctx, req.(*PingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Warden_Echo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EchoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WardenServer).Echo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/protocol.Warden/Echo"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WardenServer).Echo(ctx, req.(*EchoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Warden_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WardenServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/protocol.Warden/Create"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WardenServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Warden_Destroy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DestroyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WardenServer).Destroy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/protocol.Warden/Destroy"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WardenServer).Destroy(ctx, req.(*DestroyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Warden_Spawn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpawnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WardenServer).Spawn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/protocol.Warden/Spawn"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WardenServer).Spawn(ctx, req.(*SpawnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Warden_NetIn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetInRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WardenServer).NetIn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/protocol.Warden/NetIn"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WardenServer).NetIn(ctx, req.(*NetInRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Warden_NetOut_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetOutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WardenServer).NetOut(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/protocol.Warden/NetOut"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WardenServer).NetOut(ctx, req.(*NetOutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Warden_LimitMemory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LimitMemoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WardenServer).LimitMemory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/protocol.Warden/LimitMemory"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WardenServer).LimitMemory(ctx, req.(*LimitMemoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Warden_Stream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WardenServer).Stream(m, &wardenStreamServer{stream})
+}
+
+type wardenStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *wardenStreamServer) Send(m *StreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Warden_Run_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RunRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WardenServer).Run(m, &wardenRunServer{stream})
+}
+
+type wardenRunServer struct {
+	grpc.ServerStream
+}
+
+func (x *wardenRunServer) Send(m *StreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Warden_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "protocol.Warden",
+	HandlerType: (*WardenServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Ping", Handler: _Warden_Ping_Handler},
+		{MethodName: "Echo", Handler: _Warden_Echo_Handler},
+		{MethodName: "Create", Handler: _Warden_Create_Handler},
+		{MethodName: "Destroy", Handler: _Warden_Destroy_Handler},
+		{MethodName: "Spawn", Handler: _Warden_Spawn_Handler},
+		{MethodName: "NetIn", Handler: _Warden_NetIn_Handler},
+		{MethodName: "NetOut", Handler: _Warden_NetOut_Handler},
+		{MethodName: "LimitMemory", Handler: _Warden_LimitMemory_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stream",
+			Handler:       _Warden_Stream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Run",
+			Handler:       _Warden_Run_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "protocol/warden.proto",
+}
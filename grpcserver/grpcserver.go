@@ -0,0 +1,230 @@
+// Package grpcserver exposes Warden operations as a gRPC service instead of
+// framed messages on a Unix socket. It is driven by the same backend.Backend
+// implementation as server.WardenServer, so either transport can sit in front
+// of any backend. The two do not serve the same set of operations: this
+// service adds Spawn, Stream, Run, NetIn, NetOut and LimitMemory, and has no
+// Attach, StreamIn or StreamOut.
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/vito/garden/backend"
+	"github.com/vito/garden/grpcserver/gardenpb"
+)
+
+// GRPCServer hosts the Warden gRPC service on addr.
+type GRPCServer struct {
+	addr    string
+	backend backend.Backend
+
+	server   *grpc.Server
+	listener net.Listener
+}
+
+// NewGRPC returns a GRPCServer that will listen on addr (e.g. ":7777").
+func NewGRPC(addr string, backend backend.Backend) *GRPCServer {
+	return &GRPCServer{
+		addr:    addr,
+		backend: backend,
+	}
+}
+
+// Start listens on addr and begins serving gRPC requests in the
+// background. It returns as soon as the socket is ready, or if listening
+// fails.
+func (s *GRPCServer) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	s.listener = listener
+	s.server = grpc.NewServer()
+
+	gardenpb.RegisterWardenServer(s.server, &wardenService{backend: s.backend})
+
+	go s.server.Serve(listener)
+
+	return nil
+}
+
+// Stop gracefully shuts the gRPC server down.
+func (s *GRPCServer) Stop() {
+	s.server.GracefulStop()
+}
+
+// Addr returns the address the server ended up listening on, which is
+// useful when NewGRPC was given a ":0" port.
+func (s *GRPCServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// wardenService adapts a backend.Backend to the generated WardenServer
+// interface.
+type wardenService struct {
+	gardenpb.UnimplementedWardenServer
+
+	backend backend.Backend
+}
+
+func (s *wardenService) Ping(ctx context.Context, req *gardenpb.PingRequest) (*gardenpb.PingResponse, error) {
+	return &gardenpb.PingResponse{}, nil
+}
+
+func (s *wardenService) Echo(ctx context.Context, req *gardenpb.EchoRequest) (*gardenpb.EchoResponse, error) {
+	return &gardenpb.EchoResponse{Message: req.Message}, nil
+}
+
+func (s *wardenService) Create(ctx context.Context, req *gardenpb.CreateRequest) (*gardenpb.CreateResponse, error) {
+	bindMounts := make([]backend.BindMount, len(req.BindMounts))
+
+	for i, bindMount := range req.BindMounts {
+		mode := backend.BindMountModeRO
+		if bindMount.Mode == gardenpb.CreateRequest_BindMount_RW {
+			mode = backend.BindMountModeRW
+		}
+
+		bindMounts[i] = backend.BindMount{
+			SrcPath: bindMount.SrcPath,
+			DstPath: bindMount.DstPath,
+			Mode:    mode,
+		}
+	}
+
+	container, err := s.backend.Create(backend.ContainerSpec{
+		Handle:     req.Handle,
+		GraceTime:  time.Duration(req.GraceTime) * time.Second,
+		Network:    req.Network,
+		RootFSPath: req.Rootfs,
+		BindMounts: bindMounts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &gardenpb.CreateResponse{Handle: container.Handle()}, nil
+}
+
+func (s *wardenService) Destroy(ctx context.Context, req *gardenpb.DestroyRequest) (*gardenpb.DestroyResponse, error) {
+	if err := s.backend.Destroy(req.Handle, backend.AuthContext{}); err != nil {
+		return nil, err
+	}
+
+	return &gardenpb.DestroyResponse{}, nil
+}
+
+func (s *wardenService) Spawn(ctx context.Context, req *gardenpb.SpawnRequest) (*gardenpb.SpawnResponse, error) {
+	container, err := s.backend.Lookup(req.Handle)
+	if err != nil {
+		return nil, err
+	}
+
+	jobID, err := container.Spawn(backend.JobSpec{Script: req.Script})
+	if err != nil {
+		return nil, err
+	}
+
+	return &gardenpb.SpawnResponse{JobId: jobID}, nil
+}
+
+// Stream server-streams the stdout/stderr/exit chunks of an already
+// spawned job as they happen, ending with an EXIT message.
+func (s *wardenService) Stream(req *gardenpb.StreamRequest, stream gardenpb.Warden_StreamServer) error {
+	container, err := s.backend.Lookup(req.Handle)
+	if err != nil {
+		return err
+	}
+
+	jobStream, err := container.Stream(req.JobId)
+	if err != nil {
+		return err
+	}
+
+	for chunk := range jobStream {
+		source := gardenpb.StreamResponse_STDOUT
+		if chunk.Name == "stderr" {
+			source = gardenpb.StreamResponse_STDERR
+		}
+
+		if err := stream.Send(&gardenpb.StreamResponse{Source: source, Data: chunk.Data}); err != nil {
+			return err
+		}
+
+		if chunk.ExitStatus != nil {
+			return stream.Send(&gardenpb.StreamResponse{
+				Source:     gardenpb.StreamResponse_EXIT,
+				ExitStatus: *chunk.ExitStatus,
+			})
+		}
+	}
+
+	return nil
+}
+
+// Run spawns a job and streams its output to completion, combining what
+// used to be a Spawn followed by a Stream into a single RPC.
+func (s *wardenService) Run(req *gardenpb.RunRequest, stream gardenpb.Warden_RunServer) error {
+	container, err := s.backend.Lookup(req.Handle)
+	if err != nil {
+		return err
+	}
+
+	jobID, err := container.Spawn(backend.JobSpec{Script: req.Script})
+	if err != nil {
+		return err
+	}
+
+	return s.Stream(&gardenpb.StreamRequest{Handle: req.Handle, JobId: jobID}, stream)
+}
+
+func (s *wardenService) NetIn(ctx context.Context, req *gardenpb.NetInRequest) (*gardenpb.NetInResponse, error) {
+	container, err := s.backend.Lookup(req.Handle)
+	if err != nil {
+		return nil, err
+	}
+
+	hostPort, containerPort, err := container.NetIn(req.HostPort, req.ContainerPort)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gardenpb.NetInResponse{HostPort: hostPort, ContainerPort: containerPort}, nil
+}
+
+func (s *wardenService) NetOut(ctx context.Context, req *gardenpb.NetOutRequest) (*gardenpb.NetOutResponse, error) {
+	container, err := s.backend.Lookup(req.Handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := container.NetOut(req.Network, req.Port); err != nil {
+		return nil, err
+	}
+
+	return &gardenpb.NetOutResponse{}, nil
+}
+
+func (s *wardenService) LimitMemory(ctx context.Context, req *gardenpb.LimitMemoryRequest) (*gardenpb.LimitMemoryResponse, error) {
+	container, err := s.backend.Lookup(req.Handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.LimitInBytes != 0 {
+		if err := container.LimitMemory(req.LimitInBytes); err != nil {
+			return nil, err
+		}
+	}
+
+	limit, err := container.CurrentMemoryLimit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &gardenpb.LimitMemoryResponse{LimitInBytes: limit}, nil
+}
@@ -0,0 +1,64 @@
+package grpcserver_test
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vito/garden/backend/fakebackend"
+	"github.com/vito/garden/grpcserver"
+	"github.com/vito/garden/grpcserver/gardenpb"
+)
+
+var _ = Describe("The Warden gRPC server", func() {
+	var serverBackend *fakebackend.FakeBackend
+	var client gardenpb.WardenClient
+
+	BeforeEach(func() {
+		serverBackend = fakebackend.New()
+
+		grpcServer := grpcserver.NewGRPC("127.0.0.1:0", serverBackend)
+
+		err := grpcServer.Start()
+		Expect(err).ToNot(HaveOccurred())
+
+		conn, err := grpc.Dial(grpcServer.Addr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		Expect(err).ToNot(HaveOccurred())
+
+		client = gardenpb.NewWardenClient(conn)
+	})
+
+	Context("and the client sends a PingRequest", func() {
+		It("sends a PingResponse", func() {
+			_, err := client.Ping(context.Background(), &gardenpb.PingRequest{})
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("and the client sends an EchoRequest", func() {
+		It("sends an EchoResponse with the same message", func() {
+			response, err := client.Echo(context.Background(), &gardenpb.EchoRequest{Message: "Hello, world!"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(response.Message).To(Equal("Hello, world!"))
+		})
+	})
+
+	Context("and the client sends a CreateRequest", func() {
+		It("sends a CreateResponse with the created handle", func() {
+			response, err := client.Create(context.Background(), &gardenpb.CreateRequest{
+				Handle: "some-handle",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(response.Handle).To(Equal("some-handle"))
+
+			_, found := serverBackend.CreatedContainers["some-handle"]
+			Expect(found).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,50 @@
+// Package messagereader decodes the framed messages written by
+// protocol.Messages, surfacing server-side failures as a WardenError
+// rather than a decode error.
+package messagereader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"io"
+)
+
+// WardenError is returned when the server reports a failure in place of
+// the expected response.
+type WardenError struct {
+	Message string
+}
+
+func (e *WardenError) Error() string {
+	return e.Message
+}
+
+type envelope struct {
+	ErrMsg  string
+	Payload []byte
+}
+
+// ReadMessage reads the next framed message from r. If the server
+// reported an error for this message, ReadMessage returns a *WardenError
+// and leaves response untouched; otherwise it decodes the payload into
+// response.
+func ReadMessage(r io.Reader, response interface{}) error {
+	var length uint32
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return err
+	}
+
+	body := io.LimitReader(r, int64(length))
+
+	var env envelope
+	if err := gob.NewDecoder(body).Decode(&env); err != nil {
+		return err
+	}
+
+	if env.ErrMsg != "" {
+		return &WardenError{Message: env.ErrMsg}
+	}
+
+	return gob.NewDecoder(bytes.NewReader(env.Payload)).Decode(response)
+}
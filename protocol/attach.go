@@ -0,0 +1,106 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxAttachFrameSize caps the length an attach frame may declare, so a
+// frame lying about its size can't force an outsized allocation; it
+// matches the pump buffer size used on both ends.
+const MaxAttachFrameSize = 4096
+
+// ErrAttachFrameTooLarge is returned by ReadAttachFrame when a frame's
+// declared length exceeds MaxAttachFrameSize.
+var ErrAttachFrameTooLarge = errors.New("attach frame exceeds the maximum frame size")
+
+// AttachRequest asks the server to hand over the connection to a
+// multiplexed stdio session for an already-running job.
+type AttachRequest struct {
+	Handle           *string
+	JobId            *uint32
+	XXX_unrecognized []byte
+}
+
+func (m *AttachRequest) Reset()         { *m = AttachRequest{} }
+func (m *AttachRequest) String() string { return "AttachRequest{}" }
+func (*AttachRequest) ProtoMessage()    {}
+
+func (m *AttachRequest) GetHandle() string {
+	if m != nil && m.Handle != nil {
+		return *m.Handle
+	}
+	return ""
+}
+
+func (m *AttachRequest) GetJobId() uint32 {
+	if m != nil && m.JobId != nil {
+		return *m.JobId
+	}
+	return 0
+}
+
+// AttachResponse acknowledges an AttachRequest; once it's written, the
+// connection carries AttachFrames instead of further Envelopes.
+type AttachResponse struct {
+	XXX_unrecognized []byte
+}
+
+func (m *AttachResponse) Reset()         { *m = AttachResponse{} }
+func (m *AttachResponse) String() string { return "AttachResponse{}" }
+func (*AttachResponse) ProtoMessage()    {}
+
+// AttachStreamID identifies which of a job's stdio streams an AttachFrame
+// belongs to.
+type AttachStreamID byte
+
+const (
+	AttachStreamStdin  AttachStreamID = 0
+	AttachStreamStdout AttachStreamID = 1
+	AttachStreamStderr AttachStreamID = 2
+	AttachStreamExit   AttachStreamID = 3
+)
+
+// WriteAttachFrame writes a single tagged stdio chunk: a 1-byte stream
+// ID, a 4-byte big-endian length, and that many bytes of data.
+func WriteAttachFrame(w io.Writer, stream AttachStreamID, data []byte) error {
+	if _, err := w.Write([]byte{byte(stream)}); err != nil {
+		return err
+	}
+
+	if err := binary.Write(w, binary.BigEndian, uint32(len(data))); err != nil {
+		return err
+	}
+
+	_, err := w.Write(data)
+	return err
+}
+
+// ReadAttachFrame reads a single frame written by WriteAttachFrame. The
+// declared length is checked against MaxAttachFrameSize before the data
+// is allocated or read, so a frame lying about its size can't force an
+// outsized allocation.
+func ReadAttachFrame(r io.Reader) (AttachStreamID, []byte, error) {
+	var header [1]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+
+	var length uint32
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return 0, nil, err
+	}
+
+	if length > MaxAttachFrameSize {
+		return 0, nil, fmt.Errorf("%w: frame declares %d bytes, max is %d", ErrAttachFrameTooLarge, length, MaxAttachFrameSize)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, err
+	}
+
+	return AttachStreamID(header[0]), data, nil
+}
@@ -0,0 +1,63 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+)
+
+// Envelope is the framing unit put on the wire for every message, request
+// or response alike: a length-prefixed, self-contained gob blob tagged
+// with its Go type name so the reading side can dispatch on it, with an
+// error string set instead of a payload when the server is reporting a
+// WardenError back to the client.
+type Envelope struct {
+	Type    string
+	ErrMsg  string
+	Payload []byte
+}
+
+// MessageWriter frames a single message for the wire. Use Messages to
+// build one.
+type MessageWriter struct {
+	message interface{}
+}
+
+// Messages wraps message so it can be written to a connection with
+// WriteTo. If message is an error, it is framed as a WardenError instead
+// of a normal payload.
+func Messages(message interface{}) *MessageWriter {
+	return &MessageWriter{message: message}
+}
+
+func (w *MessageWriter) WriteTo(out io.Writer) (int64, error) {
+	env := Envelope{Type: fmt.Sprintf("%T", w.message)}
+
+	if err, ok := w.message.(error); ok {
+		env.ErrMsg = err.Error()
+	} else {
+		var payload bytes.Buffer
+		if err := gob.NewEncoder(&payload).Encode(w.message); err != nil {
+			return 0, err
+		}
+		env.Payload = payload.Bytes()
+	}
+
+	var body bytes.Buffer
+	if err := gob.NewEncoder(&body).Encode(env); err != nil {
+		return 0, err
+	}
+
+	var header bytes.Buffer
+	binary.Write(&header, binary.BigEndian, uint32(body.Len()))
+
+	n1, err := out.Write(header.Bytes())
+	if err != nil {
+		return int64(n1), err
+	}
+
+	n2, err := out.Write(body.Bytes())
+	return int64(n1 + n2), err
+}
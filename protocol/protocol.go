@@ -0,0 +1,174 @@
+// Package protocol defines the wire messages exchanged between a Warden
+// client and server. The message types in this package are hand-written
+// Go structs in the shape of protobuf-generated code; they are framed and
+// gob-encoded on the wire by Messages (see messages.go). This file holds
+// the Ping, Echo, Create and Destroy messages.
+package protocol
+
+type PingRequest struct {
+	XXX_unrecognized []byte
+}
+
+func (m *PingRequest) Reset()         { *m = PingRequest{} }
+func (m *PingRequest) String() string { return "PingRequest{}" }
+func (*PingRequest) ProtoMessage()    {}
+
+type PingResponse struct {
+	XXX_unrecognized []byte
+}
+
+func (m *PingResponse) Reset()         { *m = PingResponse{} }
+func (m *PingResponse) String() string { return "PingResponse{}" }
+func (*PingResponse) ProtoMessage()    {}
+
+type EchoRequest struct {
+	Message          *string
+	XXX_unrecognized []byte
+}
+
+func (m *EchoRequest) Reset()         { *m = EchoRequest{} }
+func (m *EchoRequest) String() string { return "EchoRequest{}" }
+func (*EchoRequest) ProtoMessage()    {}
+
+func (m *EchoRequest) GetMessage() string {
+	if m != nil && m.Message != nil {
+		return *m.Message
+	}
+	return ""
+}
+
+type EchoResponse struct {
+	Message          *string
+	XXX_unrecognized []byte
+}
+
+func (m *EchoResponse) Reset()         { *m = EchoResponse{} }
+func (m *EchoResponse) String() string { return "EchoResponse{}" }
+func (*EchoResponse) ProtoMessage()    {}
+
+func (m *EchoResponse) GetMessage() string {
+	if m != nil && m.Message != nil {
+		return *m.Message
+	}
+	return ""
+}
+
+type CreateRequest struct {
+	Handle           *string
+	GraceTime        *uint32
+	Network          *string
+	Rootfs           *string
+	BindMounts       []*CreateRequest_BindMount
+	XXX_unrecognized []byte
+}
+
+func (m *CreateRequest) Reset()         { *m = CreateRequest{} }
+func (m *CreateRequest) String() string { return "CreateRequest{}" }
+func (*CreateRequest) ProtoMessage()    {}
+
+func (m *CreateRequest) GetHandle() string {
+	if m != nil && m.Handle != nil {
+		return *m.Handle
+	}
+	return ""
+}
+
+func (m *CreateRequest) GetGraceTime() uint32 {
+	if m != nil && m.GraceTime != nil {
+		return *m.GraceTime
+	}
+	return 0
+}
+
+func (m *CreateRequest) GetNetwork() string {
+	if m != nil && m.Network != nil {
+		return *m.Network
+	}
+	return ""
+}
+
+func (m *CreateRequest) GetRootfs() string {
+	if m != nil && m.Rootfs != nil {
+		return *m.Rootfs
+	}
+	return ""
+}
+
+type CreateRequest_BindMount struct {
+	SrcPath          *string
+	DstPath          *string
+	Mode             *CreateRequest_BindMount_Mode
+	XXX_unrecognized []byte
+}
+
+func (m *CreateRequest_BindMount) Reset()         { *m = CreateRequest_BindMount{} }
+func (m *CreateRequest_BindMount) String() string { return "CreateRequest_BindMount{}" }
+func (*CreateRequest_BindMount) ProtoMessage()    {}
+
+func (m *CreateRequest_BindMount) GetSrcPath() string {
+	if m != nil && m.SrcPath != nil {
+		return *m.SrcPath
+	}
+	return ""
+}
+
+func (m *CreateRequest_BindMount) GetDstPath() string {
+	if m != nil && m.DstPath != nil {
+		return *m.DstPath
+	}
+	return ""
+}
+
+func (m *CreateRequest_BindMount) GetMode() CreateRequest_BindMount_Mode {
+	if m != nil && m.Mode != nil {
+		return *m.Mode
+	}
+	return CreateRequest_BindMount_RO
+}
+
+type CreateRequest_BindMount_Mode int32
+
+const (
+	CreateRequest_BindMount_RO CreateRequest_BindMount_Mode = 0
+	CreateRequest_BindMount_RW CreateRequest_BindMount_Mode = 1
+)
+
+type DestroyRequest struct {
+	Handle           *string
+	XXX_unrecognized []byte
+}
+
+func (m *DestroyRequest) Reset()         { *m = DestroyRequest{} }
+func (m *DestroyRequest) String() string { return "DestroyRequest{}" }
+func (*DestroyRequest) ProtoMessage()    {}
+
+func (m *DestroyRequest) GetHandle() string {
+	if m != nil && m.Handle != nil {
+		return *m.Handle
+	}
+	return ""
+}
+
+type DestroyResponse struct {
+	XXX_unrecognized []byte
+}
+
+func (m *DestroyResponse) Reset()         { *m = DestroyResponse{} }
+func (m *DestroyResponse) String() string { return "DestroyResponse{}" }
+func (*DestroyResponse) ProtoMessage()    {}
+
+type CreateResponse struct {
+	Handle           *string
+	XXX_unrecognized []byte
+}
+
+func (m *CreateResponse) Reset()         { *m = CreateResponse{} }
+func (m *CreateResponse) String() string { return "CreateResponse{}" }
+func (*CreateResponse) ProtoMessage()    {}
+
+func (m *CreateResponse) GetHandle() string {
+	if m != nil && m.Handle != nil {
+		return *m.Handle
+	}
+	return ""
+}
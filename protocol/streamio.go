@@ -0,0 +1,121 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrChunkTooLarge is returned by ReadStreamChunk when a chunk's declared
+// length exceeds the caller's maxLen.
+var ErrChunkTooLarge = errors.New("stream chunk exceeds the upload budget")
+
+// StreamInRequest asks the server to write the bytes in the StreamChunks
+// that follow to dstPath inside the container, tar'd in by the backend.
+type StreamInRequest struct {
+	Handle           *string
+	DstPath          *string
+	XXX_unrecognized []byte
+}
+
+func (m *StreamInRequest) Reset()         { *m = StreamInRequest{} }
+func (m *StreamInRequest) String() string { return "StreamInRequest{}" }
+func (*StreamInRequest) ProtoMessage()    {}
+
+func (m *StreamInRequest) GetHandle() string {
+	if m != nil && m.Handle != nil {
+		return *m.Handle
+	}
+	return ""
+}
+
+func (m *StreamInRequest) GetDstPath() string {
+	if m != nil && m.DstPath != nil {
+		return *m.DstPath
+	}
+	return ""
+}
+
+// StreamInResponse acknowledges a completed StreamInRequest.
+type StreamInResponse struct {
+	XXX_unrecognized []byte
+}
+
+func (m *StreamInResponse) Reset()         { *m = StreamInResponse{} }
+func (m *StreamInResponse) String() string { return "StreamInResponse{}" }
+func (*StreamInResponse) ProtoMessage()    {}
+
+// StreamOutRequest asks the server to tar srcPath out of the container
+// and send it back as a sequence of StreamChunks.
+type StreamOutRequest struct {
+	Handle           *string
+	SrcPath          *string
+	XXX_unrecognized []byte
+}
+
+func (m *StreamOutRequest) Reset()         { *m = StreamOutRequest{} }
+func (m *StreamOutRequest) String() string { return "StreamOutRequest{}" }
+func (*StreamOutRequest) ProtoMessage()    {}
+
+func (m *StreamOutRequest) GetHandle() string {
+	if m != nil && m.Handle != nil {
+		return *m.Handle
+	}
+	return ""
+}
+
+func (m *StreamOutRequest) GetSrcPath() string {
+	if m != nil && m.SrcPath != nil {
+		return *m.SrcPath
+	}
+	return ""
+}
+
+// WriteStreamChunk writes a single chunk of a streamed file: a 4-byte
+// big-endian length, that many bytes of data, and a trailing EOF byte
+// (1 if this is the final chunk).
+func WriteStreamChunk(w io.Writer, data []byte, eof bool) error {
+	if err := binary.Write(w, binary.BigEndian, uint32(len(data))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	eofByte := byte(0)
+	if eof {
+		eofByte = 1
+	}
+
+	_, err := w.Write([]byte{eofByte})
+	return err
+}
+
+// ReadStreamChunk reads a single chunk written by WriteStreamChunk.
+// maxLen caps the chunk's declared length, checked before the data is
+// allocated or read, so a chunk lying about its size can't force an
+// outsized allocation or read ahead of the caller's own budget check.
+func ReadStreamChunk(r io.Reader, maxLen uint32) (data []byte, eof bool, err error) {
+	var length uint32
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, false, err
+	}
+
+	if length > maxLen {
+		return nil, false, fmt.Errorf("%w: chunk declares %d bytes, %d remain", ErrChunkTooLarge, length, maxLen)
+	}
+
+	data = make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, false, err
+	}
+
+	var eofByte [1]byte
+	if _, err := io.ReadFull(r, eofByte[:]); err != nil {
+		return nil, false, err
+	}
+
+	return data, eofByte[0] == 1, nil
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+)
+
+// Config configures how a WardenServer listens for connections.
+type Config struct {
+	// ListenURL is a unix://, tcp://, or tls:// URI, e.g.
+	// "unix:///tmp/warden.sock", "tcp://0.0.0.0:7777", or
+	// "tls://0.0.0.0:7778".
+	ListenURL string
+
+	// TLS is only consulted when ListenURL uses the tls:// scheme.
+	TLS TLSConfig
+
+	// MaxUploadSize caps how many bytes a single StreamInRequest may
+	// write. Zero means DefaultMaxUploadSize.
+	MaxUploadSize int64
+}
+
+// DefaultMaxUploadSize is used when Config.MaxUploadSize is unset.
+const DefaultMaxUploadSize = 10 * 1024 * 1024 // 10 MiB
+
+// TLSConfig holds the server's certificate and, optionally, the CA used
+// to verify client certificates.
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+
+	// ClientCAFile, if set, enables client certificate verification
+	// against the given CA bundle.
+	ClientCAFile string
+
+	// RequireClientCert rejects connections that don't present a client
+	// certificate verifiable against ClientCAFile. It has no effect
+	// unless ClientCAFile is set.
+	RequireClientCert bool
+}
+
+func listen(cfg Config) (net.Listener, error) {
+	listenURL, err := url.Parse(cfg.ListenURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch listenURL.Scheme {
+	case "unix":
+		return net.Listen("unix", listenURL.Path)
+
+	case "tcp":
+		return net.Listen("tcp", listenURL.Host)
+
+	case "tls":
+		return listenTLS(listenURL.Host, cfg.TLS)
+
+	default:
+		return nil, fmt.Errorf("unknown listen scheme: %s", listenURL.Scheme)
+	}
+}
+
+func listenTLS(addr string, cfg TLSConfig) (net.Listener, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if cfg.ClientCAFile != "" {
+		caBytes, err := ioutil.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caBytes) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.ClientCAFile)
+		}
+
+		tlsConfig.ClientCAs = clientCAs
+
+		if cfg.RequireClientCert {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		} else {
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	return tls.Listen("tcp", addr, tlsConfig)
+}
@@ -0,0 +1,468 @@
+// Package server hosts the Warden protocol on a Unix socket, or on a TCP
+// or TLS listener (see Config), dispatching incoming requests to a
+// backend.Backend.
+package server
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vito/garden/backend"
+	"github.com/vito/garden/protocol"
+)
+
+// WardenServer serves Warden requests using the given backend, over
+// whichever transport it was constructed with.
+type WardenServer struct {
+	socketPath string
+	config     Config
+	backend    backend.Backend
+
+	listener net.Listener
+}
+
+// New returns a WardenServer that will listen on the given Unix socket
+// path. Unlike NewWithConfig with a "unix://" ListenURL, Start first
+// removes any file already at socketPath.
+func New(socketPath string, backend backend.Backend) *WardenServer {
+	return &WardenServer{
+		socketPath: socketPath,
+		backend:    backend,
+	}
+}
+
+// NewWithConfig returns a WardenServer configured to listen on
+// cfg.ListenURL, which may be a unix://, tcp://, or tls:// URI.
+func NewWithConfig(cfg Config, backend backend.Backend) *WardenServer {
+	return &WardenServer{
+		config:  cfg,
+		backend: backend,
+	}
+}
+
+// Start listens on the configured socket and begins serving connections
+// in the background. It returns as soon as the socket is ready, or if
+// listening fails.
+func (s *WardenServer) Start() error {
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+
+	s.listener = listener
+
+	go s.serve()
+
+	return nil
+}
+
+func (s *WardenServer) listen() (net.Listener, error) {
+	if s.config.ListenURL != "" {
+		return listen(s.config)
+	}
+
+	if err := os.RemoveAll(s.socketPath); err != nil {
+		return nil, err
+	}
+
+	return net.Listen("unix", s.socketPath)
+}
+
+// Stop closes the listening socket. Connections already accepted keep
+// running.
+func (s *WardenServer) Stop() error {
+	return s.listener.Close()
+}
+
+// Addr returns the address the server ended up listening on, which is
+// useful when the configured ListenURL used an ephemeral port.
+func (s *WardenServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *WardenServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.serveConnection(conn)
+	}
+}
+
+func (s *WardenServer) serveConnection(conn net.Conn) {
+	defer conn.Close()
+
+	auth, err := authContextFor(conn)
+	if err != nil {
+		return
+	}
+
+	for {
+		request, err := readRequest(conn)
+		if err != nil {
+			return
+		}
+
+		if attachRequest, ok := request.(*protocol.AttachRequest); ok {
+			s.handleAttach(conn, attachRequest)
+			return
+		}
+
+		if streamInRequest, ok := request.(*protocol.StreamInRequest); ok {
+			if !s.handleStreamIn(conn, streamInRequest) {
+				return
+			}
+			continue
+		}
+
+		if streamOutRequest, ok := request.(*protocol.StreamOutRequest); ok {
+			if !s.handleStreamOut(conn, streamOutRequest) {
+				return
+			}
+			continue
+		}
+
+		response, err := s.handle(request, auth)
+		if err != nil {
+			protocol.Messages(err).WriteTo(conn)
+			continue
+		}
+
+		protocol.Messages(response).WriteTo(conn)
+	}
+}
+
+// authContextFor derives the client's AuthContext from its verified TLS
+// certificate, if the connection is over TLS. Non-TLS connections (Unix
+// socket, plain TCP) get a zero-value AuthContext.
+func authContextFor(conn net.Conn) (backend.AuthContext, error) {
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return backend.AuthContext{}, nil
+	}
+
+	if err := tlsConn.Handshake(); err != nil {
+		return backend.AuthContext{}, err
+	}
+
+	peerCerts := tlsConn.ConnectionState().PeerCertificates
+	if len(peerCerts) == 0 {
+		return backend.AuthContext{}, nil
+	}
+
+	return backend.AuthContext{CommonName: peerCerts[0].Subject.CommonName}, nil
+}
+
+// handleAttach acknowledges an AttachRequest and then hands the rest of
+// the connection over to a multiplexed stdio session: stdin frames read
+// from conn are pushed into the job, and stdout/stderr/exit are written
+// back as frames, until the job exits.
+func (s *WardenServer) handleAttach(conn net.Conn, request *protocol.AttachRequest) {
+	container, err := s.backend.Lookup(request.GetHandle())
+	if err != nil {
+		protocol.Messages(err).WriteTo(conn)
+		return
+	}
+
+	streams, err := container.Attach(request.GetJobId(), backend.AttachOptions{})
+	if err != nil {
+		protocol.Messages(err).WriteTo(conn)
+		return
+	}
+
+	if _, err := protocol.Messages(&protocol.AttachResponse{}).WriteTo(conn); err != nil {
+		return
+	}
+
+	go func() {
+		for {
+			streamID, data, err := protocol.ReadAttachFrame(conn)
+			if err != nil {
+				streams.Stdin.Close()
+				return
+			}
+
+			if streamID != protocol.AttachStreamStdin {
+				continue
+			}
+
+			if len(data) == 0 {
+				streams.Stdin.Close()
+				return
+			}
+
+			streams.Stdin.Write(data)
+		}
+	}()
+
+	// WriteAttachFrame issues multiple Writes per frame, so stdout and
+	// stderr (and the final exit frame) must take turns on conn or their
+	// frames interleave into a stream neither side can demux.
+	var writeMu sync.Mutex
+	writeFrame := func(id protocol.AttachStreamID, data []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return protocol.WriteAttachFrame(conn, id, data)
+	}
+
+	pump := func(id protocol.AttachStreamID, r io.Reader) {
+		buf := make([]byte, 4096)
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				writeFrame(id, buf[:n])
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	done := make(chan struct{})
+	go func() { pump(protocol.AttachStreamStdout, streams.Stdout); close(done) }()
+	go pump(protocol.AttachStreamStderr, streams.Stderr)
+
+	exitStatus, _ := streams.Wait()
+	<-done
+
+	writeFrame(protocol.AttachStreamExit, []byte{byte(exitStatus)})
+}
+
+func (s *WardenServer) maxUploadSize() int64 {
+	if s.config.MaxUploadSize > 0 {
+		return s.config.MaxUploadSize
+	}
+
+	return DefaultMaxUploadSize
+}
+
+// remainingChunkBudget clamps maxSize-total into a uint32 for
+// protocol.ReadStreamChunk, which reads its chunk length off the wire as
+// a uint32; math.MaxUint32 is far above any sane MaxUploadSize, so the
+// clamp is just to keep the conversion safe.
+func remainingChunkBudget(maxSize, total int64) uint32 {
+	remaining := maxSize - total
+	if remaining > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	if remaining < 0 {
+		return 0
+	}
+	return uint32(remaining)
+}
+
+// handleStreamIn streams the StreamChunks that follow a StreamInRequest
+// straight into the backend's StreamIn (which streams them to disk,
+// rather than buffering the whole upload in memory), enforcing
+// MaxUploadSize along the way, and only then replies with a
+// StreamInResponse or a WardenError. It returns whether the connection
+// can keep serving further requests.
+func (s *WardenServer) handleStreamIn(conn net.Conn, request *protocol.StreamInRequest) bool {
+	container, err := s.backend.Lookup(request.GetHandle())
+	if err != nil {
+		protocol.Messages(err).WriteTo(conn)
+		return true
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+
+	streamInDone := make(chan error, 1)
+	go func() {
+		streamInDone <- container.StreamIn(request.GetDstPath(), pipeReader)
+	}()
+
+	maxSize := s.maxUploadSize()
+	var total int64
+
+	for {
+		data, eof, err := protocol.ReadStreamChunk(conn, remainingChunkBudget(maxSize, total))
+		if errors.Is(err, protocol.ErrChunkTooLarge) {
+			pipeWriter.CloseWithError(err)
+			<-streamInDone
+			protocol.Messages(err).WriteTo(conn)
+			return false
+		}
+		if err != nil {
+			pipeWriter.CloseWithError(err)
+			return false
+		}
+
+		total += int64(len(data))
+
+		if _, err := pipeWriter.Write(data); err != nil {
+			return false
+		}
+
+		if eof {
+			break
+		}
+	}
+
+	pipeWriter.Close()
+
+	if err := <-streamInDone; err != nil {
+		protocol.Messages(err).WriteTo(conn)
+		return true
+	}
+
+	protocol.Messages(&protocol.StreamInResponse{}).WriteTo(conn)
+
+	return true
+}
+
+// handleStreamOut tars srcPath out of the container and sends it back as
+// a sequence of StreamChunks, without buffering the whole payload in
+// memory. It returns whether the connection can keep serving further
+// requests.
+func (s *WardenServer) handleStreamOut(conn net.Conn, request *protocol.StreamOutRequest) bool {
+	container, err := s.backend.Lookup(request.GetHandle())
+	if err != nil {
+		protocol.Messages(err).WriteTo(conn)
+		return true
+	}
+
+	reader, err := container.StreamOut(request.GetSrcPath())
+	if err != nil {
+		protocol.Messages(err).WriteTo(conn)
+		return true
+	}
+	defer reader.Close()
+
+	buf := make([]byte, 32*1024)
+
+	for {
+		n, readErr := reader.Read(buf)
+		if n > 0 {
+			eof := readErr == io.EOF
+			if err := protocol.WriteStreamChunk(conn, buf[:n], eof); err != nil {
+				return false
+			}
+
+			if eof {
+				return true
+			}
+		}
+
+		if readErr != nil {
+			if readErr == io.EOF {
+				protocol.WriteStreamChunk(conn, nil, true)
+				return true
+			}
+
+			return false
+		}
+	}
+}
+
+func (s *WardenServer) handle(request interface{}, auth backend.AuthContext) (interface{}, error) {
+	switch request := request.(type) {
+	case *protocol.PingRequest:
+		return &protocol.PingResponse{}, nil
+
+	case *protocol.EchoRequest:
+		return &protocol.EchoResponse{Message: request.Message}, nil
+
+	case *protocol.CreateRequest:
+		return s.handleCreate(request, auth)
+
+	case *protocol.DestroyRequest:
+		return s.handleDestroy(request, auth)
+	}
+
+	return nil, nil
+}
+
+func (s *WardenServer) handleDestroy(request *protocol.DestroyRequest, auth backend.AuthContext) (*protocol.DestroyResponse, error) {
+	if err := s.backend.Destroy(request.GetHandle(), auth); err != nil {
+		return nil, err
+	}
+
+	return &protocol.DestroyResponse{}, nil
+}
+
+func (s *WardenServer) handleCreate(request *protocol.CreateRequest, auth backend.AuthContext) (*protocol.CreateResponse, error) {
+	bindMounts := make([]backend.BindMount, len(request.BindMounts))
+
+	for i, bindMount := range request.BindMounts {
+		mode := backend.BindMountModeRO
+		if bindMount.GetMode() == protocol.CreateRequest_BindMount_RW {
+			mode = backend.BindMountModeRW
+		}
+
+		bindMounts[i] = backend.BindMount{
+			SrcPath: bindMount.GetSrcPath(),
+			DstPath: bindMount.GetDstPath(),
+			Mode:    mode,
+		}
+	}
+
+	container, err := s.backend.Create(backend.ContainerSpec{
+		Handle:     request.GetHandle(),
+		GraceTime:  time.Duration(request.GetGraceTime()) * time.Second,
+		Network:    request.GetNetwork(),
+		RootFSPath: request.GetRootfs(),
+		BindMounts: bindMounts,
+		Auth:       auth,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &protocol.CreateResponse{Handle: proto_string(container.Handle())}, nil
+}
+
+func proto_string(s string) *string {
+	return &s
+}
+
+// readRequest reads the next framed request from conn and decodes it into
+// the concrete protocol type its envelope was tagged with.
+func readRequest(conn io.Reader) (interface{}, error) {
+	var length uint32
+	if err := binary.Read(conn, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+
+	var env protocol.Envelope
+	if err := gob.NewDecoder(io.LimitReader(conn, int64(length))).Decode(&env); err != nil {
+		return nil, err
+	}
+
+	var request interface{}
+
+	switch env.Type {
+	case "*protocol.PingRequest":
+		request = &protocol.PingRequest{}
+	case "*protocol.EchoRequest":
+		request = &protocol.EchoRequest{}
+	case "*protocol.CreateRequest":
+		request = &protocol.CreateRequest{}
+	case "*protocol.AttachRequest":
+		request = &protocol.AttachRequest{}
+	case "*protocol.DestroyRequest":
+		request = &protocol.DestroyRequest{}
+	case "*protocol.StreamInRequest":
+		request = &protocol.StreamInRequest{}
+	case "*protocol.StreamOutRequest":
+		request = &protocol.StreamOutRequest{}
+	default:
+		return nil, errors.New("unknown request type: " + env.Type)
+	}
+
+	if err := gob.NewDecoder(bytes.NewReader(env.Payload)).Decode(request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
@@ -1,6 +1,7 @@
 package server_test
 
 import (
+	"crypto/tls"
 	"errors"
 	"io/ioutil"
 	"net"
@@ -8,7 +9,7 @@ import (
 	"path"
 	"time"
 
-	"code.google.com/p/gogoprotobuf/proto"
+	"github.com/golang/protobuf/proto"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -23,22 +24,22 @@ import (
 var _ = Describe("The Warden server", func() {
 	It("listens on the given socket path", func() {
 		tmpdir, err := ioutil.TempDir(os.TempDir(), "warden-server-test")
-		Expect(err).ToNot(HaveOccured())
+		Expect(err).ToNot(HaveOccurred())
 
 		socketPath := path.Join(tmpdir, "warden.sock")
 
 		wardenServer := server.New(socketPath, fakebackend.New())
 
 		err = wardenServer.Start()
-		Expect(err).ToNot(HaveOccured())
+		Expect(err).ToNot(HaveOccurred())
 
-		Eventually(ErrorDialingUnix(socketPath)).ShouldNot(HaveOccured())
+		Eventually(ErrorDialingUnix(socketPath)).ShouldNot(HaveOccurred())
 	})
 
 	Context("when starting fails", func() {
 		It("returns the error", func() {
 			tmpfile, err := ioutil.TempFile(os.TempDir(), "warden-server-test")
-			Expect(err).ToNot(HaveOccured())
+			Expect(err).ToNot(HaveOccurred())
 
 			wardenServer := server.New(
 				// weird scenario: /foo/X/warden.sock with X being a file
@@ -47,7 +48,7 @@ var _ = Describe("The Warden server", func() {
 			)
 
 			err = wardenServer.Start()
-			Expect(err).To(HaveOccured())
+			Expect(err).To(HaveOccurred())
 		})
 	})
 
@@ -59,7 +60,7 @@ var _ = Describe("The Warden server", func() {
 
 		BeforeEach(func() {
 			tmpdir, err := ioutil.TempDir(os.TempDir(), "warden-server-test")
-			Expect(err).ToNot(HaveOccured())
+			Expect(err).ToNot(HaveOccurred())
 
 			socketPath = path.Join(tmpdir, "warden.sock")
 			serverBackend = fakebackend.New()
@@ -67,23 +68,23 @@ var _ = Describe("The Warden server", func() {
 			wardenServer := server.New(socketPath, serverBackend)
 
 			err = wardenServer.Start()
-			Expect(err).ToNot(HaveOccured())
+			Expect(err).ToNot(HaveOccurred())
 
-			Eventually(ErrorDialingUnix(socketPath)).ShouldNot(HaveOccured())
+			Eventually(ErrorDialingUnix(socketPath)).ShouldNot(HaveOccurred())
 
 			serverConnection, err = net.Dial("unix", socketPath)
-			Expect(err).ToNot(HaveOccured())
+			Expect(err).ToNot(HaveOccurred())
 		})
 
 		writeMessages := func(message proto.Message) {
 			num, err := protocol.Messages(message).WriteTo(serverConnection)
-			Expect(err).ToNot(HaveOccured())
+			Expect(err).ToNot(HaveOccurred())
 			Expect(num).ToNot(Equal(0))
 		}
 
 		readResponse := func(response proto.Message) {
 			err := messagereader.ReadMessage(serverConnection, response)
-			Expect(err).ToNot(HaveOccured())
+			Expect(err).ToNot(HaveOccurred())
 		}
 
 		Context("and the client sends a PingRequest", func() {
@@ -183,6 +184,174 @@ var _ = Describe("The Warden server", func() {
 				}, 1.0)
 			})
 		})
+
+		Context("and the client sends a StreamInRequest", func() {
+			BeforeEach(func(done Done) {
+				writeMessages(&protocol.CreateRequest{
+					Handle: proto.String("some-handle"),
+				})
+
+				var created protocol.CreateResponse
+				readResponse(&created)
+
+				close(done)
+			}, 1.0)
+
+			It("accepts an upload within MaxUploadSize", func(done Done) {
+				writeMessages(&protocol.StreamInRequest{
+					Handle:  proto.String("some-handle"),
+					DstPath: proto.String("/some/path"),
+				})
+
+				payload := make([]byte, 5*1024*1024)
+
+				err := protocol.WriteStreamChunk(serverConnection, payload, true)
+				Expect(err).ToNot(HaveOccurred())
+
+				var response protocol.StreamInResponse
+				err = messagereader.ReadMessage(serverConnection, &response)
+				Expect(err).ToNot(HaveOccurred())
+
+				container := serverBackend.CreatedContainers["some-handle"]
+				streamedOut, err := container.StreamOut("/some/path")
+				Expect(err).ToNot(HaveOccurred())
+
+				streamedBytes, err := ioutil.ReadAll(streamedOut)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(streamedBytes).To(HaveLen(len(payload)))
+
+				close(done)
+			}, 5.0)
+
+			It("rejects an upload exceeding MaxUploadSize", func(done Done) {
+				writeMessages(&protocol.StreamInRequest{
+					Handle:  proto.String("some-handle"),
+					DstPath: proto.String("/some/path"),
+				})
+
+				payload := make([]byte, 11*1024*1024)
+
+				// The server rejects the oversize chunk as soon as it reads
+				// the declared length, without reading the payload itself,
+				// so this write may fail with a broken pipe once the server
+				// hangs up - either way, the upload must not succeed.
+				protocol.WriteStreamChunk(serverConnection, payload, true)
+
+				var response protocol.StreamInResponse
+				err := messagereader.ReadMessage(serverConnection, &response)
+				Expect(err).To(HaveOccurred())
+
+				close(done)
+			}, 5.0)
+		})
+
+		Context("and the client sends an AttachRequest", func() {
+			It("streams stdin back out as stdout", func(done Done) {
+				writeMessages(&protocol.CreateRequest{
+					Handle: proto.String("some-handle"),
+				})
+
+				var created protocol.CreateResponse
+				readResponse(&created)
+
+				writeMessages(&protocol.AttachRequest{
+					Handle: proto.String(created.GetHandle()),
+					JobId:  proto.Uint32(0),
+				})
+
+				var attached protocol.AttachResponse
+				readResponse(&attached)
+
+				err := protocol.WriteAttachFrame(serverConnection, protocol.AttachStreamStdin, []byte("hello"))
+				Expect(err).ToNot(HaveOccurred())
+
+				err = protocol.WriteAttachFrame(serverConnection, protocol.AttachStreamStdin, []byte{})
+				Expect(err).ToNot(HaveOccurred())
+
+				streamID, data, err := protocol.ReadAttachFrame(serverConnection)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(streamID).To(Equal(protocol.AttachStreamStdout))
+				Expect(string(data)).To(Equal("hello"))
+
+				close(done)
+			}, 1.0)
+		})
+	})
+
+	Context("when listening over TLS", func() {
+		var tmpdir string
+		var wardenServer *server.WardenServer
+		var serverBackend *fakebackend.FakeBackend
+
+		BeforeEach(func() {
+			var err error
+			tmpdir, err = ioutil.TempDir(os.TempDir(), "warden-server-tls-test")
+			Expect(err).ToNot(HaveOccurred())
+
+			ca, _ := generateTLSFixtures(tmpdir)
+
+			serverBackend = fakebackend.New()
+
+			wardenServer = server.NewWithConfig(server.Config{
+				ListenURL: "tls://127.0.0.1:0",
+				TLS: server.TLSConfig{
+					CertFile:          path.Join(tmpdir, "server.crt"),
+					KeyFile:           path.Join(tmpdir, "server.key"),
+					ClientCAFile:      ca,
+					RequireClientCert: true,
+				},
+			}, serverBackend)
+
+			err = wardenServer.Start()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("accepts a connection presenting a valid client certificate", func() {
+			clientCert, err := tls.LoadX509KeyPair(
+				path.Join(tmpdir, "trusted-client.crt"),
+				path.Join(tmpdir, "trusted-client.key"),
+			)
+			Expect(err).ToNot(HaveOccurred())
+
+			conn, err := tls.Dial("tcp", wardenServer.Addr(), &tls.Config{
+				Certificates:       []tls.Certificate{clientCert},
+				InsecureSkipVerify: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			defer conn.Close()
+
+			num, err := protocol.Messages(&protocol.PingRequest{}).WriteTo(conn)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(num).ToNot(Equal(0))
+
+			var response protocol.PingResponse
+			err = messagereader.ReadMessage(conn, &response)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("rejects a connection presenting an untrusted client certificate", func() {
+			untrustedCert := generateSelfSignedCert("untrusted-client")
+
+			conn, err := tls.Dial("tcp", wardenServer.Addr(), &tls.Config{
+				Certificates:       []tls.Certificate{untrustedCert},
+				InsecureSkipVerify: true,
+			})
+			if err == nil {
+				defer conn.Close()
+
+				_, err = conn.Write([]byte{0})
+			}
+
+			// TLS 1.3's client-side handshake can complete, and a
+			// subsequent Write can be buffered locally, before the
+			// server's asynchronous rejection of the client cert
+			// reaches us - so a Read is needed to observe it.
+			if err == nil {
+				_, err = conn.Read(make([]byte, 1))
+			}
+
+			Expect(err).To(HaveOccurred())
+		})
 	})
 })
 
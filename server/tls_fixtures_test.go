@@ -0,0 +1,120 @@
+package server_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path"
+	"time"
+)
+
+// generateTLSFixtures writes a CA cert/key, a server cert signed by that
+// CA, and a client cert signed by that CA ("trusted-client") into dir. It
+// returns the path to the CA cert PEM, for use as a ClientCAFile, and the
+// path to the trusted client's cert PEM.
+func generateTLSFixtures(dir string) (caCertPath string, trustedClientCertPath string) {
+	caKey, caCert, caCertPEM := generateCA()
+
+	writeFile(dir, "ca.crt", caCertPEM)
+
+	writeSignedCert(dir, "server", "127.0.0.1", caCert, caKey)
+	trustedClientCertPath = writeSignedCert(dir, "trusted-client", "trusted-client", caCert, caKey)
+
+	return path.Join(dir, "ca.crt"), trustedClientCertPath
+}
+
+// generateSelfSignedCert returns a cert/key pair for commonName that
+// isn't signed by any CA the server trusts.
+func generateSelfSignedCert(commonName string) tls.Certificate {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(10, 0, 0),
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic(err)
+	}
+
+	cert, err := tls.X509KeyPair(pemEncode("CERTIFICATE", certDER), pemEncode("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)))
+	if err != nil {
+		panic(err)
+	}
+
+	return cert
+}
+
+func generateCA() (*rsa.PrivateKey, *x509.Certificate, []byte) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "warden-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic(err)
+	}
+
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		panic(err)
+	}
+
+	return key, cert, pemEncode("CERTIFICATE", certDER)
+}
+
+func writeSignedCert(dir, filename, commonName string, ca *x509.Certificate, caKey *rsa.PrivateKey) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(10, 0, 0),
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		panic(err)
+	}
+
+	writeFile(dir, filename+".crt", pemEncode("CERTIFICATE", certDER))
+	writeFile(dir, filename+".key", pemEncode("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)))
+
+	return path.Join(dir, filename+".crt")
+}
+
+func pemEncode(blockType string, der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+}
+
+func writeFile(dir, name string, data []byte) {
+	if err := ioutil.WriteFile(path.Join(dir, name), data, 0600); err != nil {
+		panic(err)
+	}
+}